@@ -0,0 +1,290 @@
+package edict
+
+import "strings"
+
+// NonPast is the form a word is listed under in the dictionary, and its negative and polite
+// variants.
+const NonPast Inflection = "non-past"
+
+// Conjugation is a single conjugated form of a word.
+type Conjugation struct {
+	Form     Inflection `json:"form"`               // The form, like Past or Potential.
+	Polite   bool       `json:"polite,omitempty"`   // True for the polite (ます) variant.
+	Negative bool       `json:"negative,omitempty"` // True for the negative variant.
+	Text     string     `json:"text"`               // The conjugated word.
+	Reading  string     `json:"reading,omitempty"`  // The conjugated reading, if the entry has one.
+}
+
+// ConjugationTable lists the forms of a word, conjugated as one of its word classes.
+type ConjugationTable struct {
+	Class   Detail        `json:"class"`             // The word class, like V5k or AdjI.
+	Word    string        `json:"word"`              // The dictionary form; for Vs nouns, with する.
+	Reading string        `json:"reading,omitempty"` // The reading of the dictionary form, if the entry has one.
+	Forms   []Conjugation `json:"forms"`
+}
+
+// verbStems holds the irregular parts of a verb's conjugation; everything else is built from them.
+type verbStems struct {
+	dictionary, negative, masu    string // 食べる, 食べない, 食べ
+	te, past                      string // 食べて, 食べた
+	imperative, volitional        string // 食べろ, 食べよう
+	potential, passive, causative string // 食べられる, 食べられる, 食べさせる; all ichidan verbs.
+}
+
+// Conjugate returns a conjugation table for each word class of e that conjugates: verbs that are
+// ichidan, godan, 来る, する or ずる, nouns that take する, and i- and na-adjectives.  Tables are built
+// from the first Kanji key, and the first Kana reading if there is one.
+func Conjugate(e Entry) []ConjugationTable {
+	var result []ConjugationTable
+	if len(e.Kanji) == 0 {
+		return result
+	}
+	reading := ""
+	if len(e.Kana) > 0 {
+		reading = e.Kana[0]
+	}
+
+	seen := make(map[Detail]bool)
+	for _, class := range entryDetails(e) {
+		if seen[class] {
+			continue
+		}
+		seen[class] = true
+
+		word, kana := e.Kanji[0], reading
+		if class == Vs {
+			word += "する"
+			if kana != "" {
+				kana += "する"
+			}
+		}
+		forms := conjugateWord(class, word)
+		if forms == nil {
+			continue
+		}
+		var readings []Conjugation
+		if kana != "" {
+			readings = conjugateWord(class, kana)
+		}
+
+		table := ConjugationTable{Class: class, Word: word, Reading: kana, Forms: forms}
+		for i := range table.Forms {
+			if i < len(readings) {
+				table.Forms[i].Reading = readings[i].Text
+			}
+		}
+		result = append(result, table)
+	}
+
+	return result
+}
+
+// entryDetails returns the details of an entry and all of its glosses.
+func entryDetails(e Entry) []Detail {
+	result := append([]Detail{}, e.Information...)
+	for _, gloss := range e.Gloss {
+		result = append(result, gloss.Information...)
+	}
+	return result
+}
+
+// conjugateWord returns the forms of word, conjugated as class, or nil if class doesn't conjugate
+// or word doesn't have the right ending for it.
+func conjugateWord(class Detail, word string) []Conjugation {
+	switch class {
+	case AdjI, AdjIx:
+		return conjugateAdjective(word)
+	case AdjNa:
+		return conjugateNa(word)
+	}
+
+	stems, ok := stemsFor(class, word)
+	if !ok {
+		return nil
+	}
+
+	ichidan := func(form Inflection, verb string) []Conjugation {
+		stem := strings.TrimSuffix(verb, "る")
+		return []Conjugation{
+			{Form: form, Text: verb},
+			{Form: form, Negative: true, Text: stem + "ない"},
+			{Form: form, Polite: true, Text: stem + "ます"},
+			{Form: form, Polite: true, Negative: true, Text: stem + "ません"},
+		}
+	}
+	negative := strings.TrimSuffix(stems.negative, "い")
+
+	var result []Conjugation
+	result = append(result,
+		Conjugation{Form: NonPast, Text: stems.dictionary},
+		Conjugation{Form: NonPast, Negative: true, Text: stems.negative},
+		Conjugation{Form: NonPast, Polite: true, Text: stems.masu + "ます"},
+		Conjugation{Form: NonPast, Polite: true, Negative: true, Text: stems.masu + "ません"},
+		Conjugation{Form: Past, Text: stems.past},
+		Conjugation{Form: Past, Negative: true, Text: negative + "かった"},
+		Conjugation{Form: Past, Polite: true, Text: stems.masu + "ました"},
+		Conjugation{Form: Past, Polite: true, Negative: true, Text: stems.masu + "ませんでした"},
+		Conjugation{Form: Te, Text: stems.te},
+		Conjugation{Form: Te, Negative: true, Text: negative + "くて"},
+	)
+	result = append(result, ichidan(Potential, stems.potential)...)
+	result = append(result, ichidan(Passive, stems.passive)...)
+	result = append(result, ichidan(Causative, stems.causative)...)
+	result = append(result,
+		Conjugation{Form: Imperative, Text: stems.imperative},
+		Conjugation{Form: Imperative, Negative: true, Text: stems.dictionary + "な"},
+		Conjugation{Form: Imperative, Polite: true, Text: stems.te + "ください"},
+		Conjugation{Form: Imperative, Polite: true, Negative: true, Text: stems.negative + "でください"},
+		Conjugation{Form: Volitional, Text: stems.volitional},
+		Conjugation{Form: Volitional, Polite: true, Text: stems.masu + "ましょう"},
+	)
+	return result
+}
+
+// stemsFor works out the stems of a verb of the given class.
+func stemsFor(class Detail, word string) (verbStems, bool) {
+	switch {
+	case class == V1:
+		stem := strings.TrimSuffix(word, "る")
+		if stem == word {
+			return verbStems{}, false
+		}
+		return verbStems{
+			dictionary: word, negative: stem + "ない", masu: stem,
+			te: stem + "て", past: stem + "た",
+			imperative: stem + "ろ", volitional: stem + "よう",
+			potential: stem + "られる", passive: stem + "られる", causative: stem + "させる",
+		}, true
+
+	case class == Vk:
+		// 来る keeps its kanji, but its reading changes from く to こ or き.
+		ko, ki := "来", "来"
+		prefix := strings.TrimSuffix(word, "来る")
+		if prefix == word {
+			ko, ki = "こ", "き"
+			prefix = strings.TrimSuffix(word, "くる")
+			if prefix == word {
+				return verbStems{}, false
+			}
+		}
+		ko, ki = prefix+ko, prefix+ki
+		return verbStems{
+			dictionary: word, negative: ko + "ない", masu: ki,
+			te: ki + "て", past: ki + "た",
+			imperative: ko + "い", volitional: ko + "よう",
+			potential: ko + "られる", passive: ko + "られる", causative: ko + "させる",
+		}, true
+
+	case class == Vs || class == VsI || class == VsS:
+		// Like 来る, 為る keeps its kanji for し and さ; its potential is 出来る.
+		shi, sa, deki := "為", "為", "出来"
+		prefix := strings.TrimSuffix(word, "為る")
+		if prefix == word {
+			shi, sa, deki = "し", "さ", "でき"
+			prefix = strings.TrimSuffix(word, "する")
+			if prefix == word {
+				return verbStems{}, false
+			}
+		}
+		shi, sa, deki = prefix+shi, prefix+sa, prefix+deki
+		stems := verbStems{
+			dictionary: word, negative: shi + "ない", masu: shi,
+			te: shi + "て", past: shi + "た",
+			imperative: shi + "ろ", volitional: shi + "よう",
+			potential: deki + "る", passive: sa + "れる", causative: sa + "せる",
+		}
+		if class == VsS {
+			// 愛する borrows from the godan 愛す: 愛さない, 愛せる, 愛せ.
+			stems.negative = sa + "ない"
+			stems.potential = prefix + "せる"
+			stems.imperative = prefix + "せ"
+		}
+		return stems, true
+
+	case class == Vz:
+		stem := strings.TrimSuffix(word, "ずる")
+		if stem == word {
+			return verbStems{}, false
+		}
+		stem += "じ"
+		return verbStems{
+			dictionary: word, negative: stem + "ない", masu: stem,
+			te: stem + "て", past: stem + "た",
+			imperative: stem + "ろ", volitional: stem + "よう",
+			potential: stem + "られる", passive: stem + "られる", causative: stem + "させる",
+		}, true
+
+	case detailTypes[class]&(typeV5|typeV5kS) != 0:
+		runes := []rune(word)
+		if len(runes) == 0 {
+			return verbStems{}, false
+		}
+		base, ending := string(runes[:len(runes)-1]), string(runes[len(runes)-1])
+		for _, row := range godanRows {
+			if row.u != ending {
+				continue
+			}
+			stems := verbStems{
+				dictionary: word, negative: base + row.a + "ない", masu: base + row.i,
+				te: base + row.te, past: base + pastOf(row.te),
+				imperative: base + row.e, volitional: base + row.o + "う",
+				potential: base + row.e + "る", passive: base + row.a + "れる", causative: base + row.a + "せる",
+			}
+			switch class {
+			case V5kS:
+				stems.te, stems.past = base+"って", base+"った"
+			case V5uS:
+				stems.te, stems.past = base+"うて", base+"うた"
+			case V5rI:
+				// 有る: the negative is just ない.
+				stems.negative = "ない"
+			case V5aru:
+				// くださる: ください, くださいます.
+				stems.masu, stems.imperative = base+"い", base+"い"
+			}
+			return stems, true
+		}
+	}
+
+	return verbStems{}, false
+}
+
+// conjugateAdjective conjugates an i-adjective.  いい, and compounds like かっこいい, conjugate from
+// よい.
+func conjugateAdjective(word string) []Conjugation {
+	stem := strings.TrimSuffix(word, "い")
+	if stem == word {
+		return nil
+	}
+	if strings.HasSuffix(word, "いい") {
+		stem = strings.TrimSuffix(word, "いい") + "よ"
+	}
+	return []Conjugation{
+		{Form: NonPast, Text: word},
+		{Form: NonPast, Negative: true, Text: stem + "くない"},
+		{Form: NonPast, Polite: true, Text: word + "です"},
+		{Form: NonPast, Polite: true, Negative: true, Text: stem + "くありません"},
+		{Form: Past, Text: stem + "かった"},
+		{Form: Past, Negative: true, Text: stem + "くなかった"},
+		{Form: Past, Polite: true, Text: stem + "かったです"},
+		{Form: Past, Polite: true, Negative: true, Text: stem + "くありませんでした"},
+		{Form: Te, Text: stem + "くて"},
+		{Form: Te, Negative: true, Text: stem + "くなくて"},
+	}
+}
+
+// conjugateNa conjugates a na-adjective, with the copula.
+func conjugateNa(word string) []Conjugation {
+	return []Conjugation{
+		{Form: NonPast, Text: word + "だ"},
+		{Form: NonPast, Negative: true, Text: word + "じゃない"},
+		{Form: NonPast, Polite: true, Text: word + "です"},
+		{Form: NonPast, Polite: true, Negative: true, Text: word + "じゃありません"},
+		{Form: Past, Text: word + "だった"},
+		{Form: Past, Negative: true, Text: word + "じゃなかった"},
+		{Form: Past, Polite: true, Text: word + "でした"},
+		{Form: Past, Polite: true, Negative: true, Text: word + "じゃありませんでした"},
+		{Form: Te, Text: word + "で"},
+		{Form: Te, Negative: true, Text: word + "じゃなくて"},
+	}
+}
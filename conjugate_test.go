@@ -0,0 +1,125 @@
+package edict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConjugate(t *testing.T) {
+	type form struct {
+		Form     Inflection
+		Polite   bool
+		Negative bool
+	}
+	testData := []struct {
+		line  string
+		class Detail
+		want  map[form][2]string // Text and Reading.
+	}{
+		{"食べる [たべる] /(v1,vt) to eat/(P)/EntL1358280X/", V1, map[form][2]string{
+			{NonPast, false, true}:     {"食べない", "たべない"},
+			{Past, true, true}:         {"食べませんでした", "たべませんでした"},
+			{Potential, false, false}:  {"食べられる", "たべられる"},
+			{Imperative, false, false}: {"食べろ", "たべろ"},
+		}},
+		{"買う [かう] /(v5u,vt) to buy/(P)/EntL1202820X/", V5u, map[form][2]string{
+			{NonPast, false, true}:     {"買わない", "かわない"},
+			{Te, false, false}:         {"買って", "かって"},
+			{Causative, false, false}:  {"買わせる", "かわせる"},
+			{Volitional, false, false}: {"買おう", "かおう"},
+		}},
+		{"行く [いく] /(v5k-s,vi) to go/(P)/EntL1578850X/", V5kS, map[form][2]string{
+			{Te, false, false}:       {"行って", "いって"},
+			{Past, false, false}:     {"行った", "いった"},
+			{NonPast, true, false}:   {"行きます", "いきます"},
+			{Potential, false, true}: {"行けない", "いけない"},
+		}},
+		{"有る [ある] /(v5r-i,vi) to exist/(P)/EntL1296400X/", V5rI, map[form][2]string{
+			{NonPast, false, true}: {"ない", "ない"},
+			{Past, false, true}:    {"なかった", "なかった"},
+			{Past, true, false}:    {"有りました", "ありました"},
+		}},
+		{"下さる [くださる] /(v5aru,vt) (hon) to give/(P)/EntL1184270X/", V5aru, map[form][2]string{
+			{Imperative, false, false}: {"下さい", "ください"},
+			{NonPast, true, false}:     {"下さいます", "くださいます"},
+			{NonPast, false, true}:     {"下さらない", "くださらない"},
+		}},
+		{"来る [くる] /(vk,vi) to come/(P)/EntL1547720X/", Vk, map[form][2]string{
+			{NonPast, false, true}:     {"来ない", "こない"},
+			{Past, false, false}:       {"来た", "きた"},
+			{Passive, false, false}:    {"来られる", "こられる"},
+			{Imperative, false, false}: {"来い", "こい"},
+		}},
+		{"信ずる [しんずる] /(vz,vt) to believe/EntL1360580X/", Vz, map[form][2]string{
+			{NonPast, false, true}:    {"信じない", "しんじない"},
+			{Te, false, false}:        {"信じて", "しんじて"},
+			{Potential, false, false}: {"信じられる", "しんじられる"},
+		}},
+		{"良い [いい] /(adj-ix) good/(P)/EntL2820690X/", AdjIx, map[form][2]string{
+			{Past, false, false}: {"良かった", "よかった"},
+		}},
+		{"する /(vs-i) to do/(P)/EntL1157170X/", VsI, map[form][2]string{
+			{NonPast, false, true}:    {"しない", ""},
+			{Potential, false, false}: {"できる", ""},
+			{Volitional, true, false}: {"しましょう", ""},
+		}},
+		{"為る [する] /(vs-i,vt) (uk) to do/(P)/EntL1157170X/", VsI, map[form][2]string{
+			{NonPast, false, true}:    {"為ない", "しない"},
+			{Past, false, false}:      {"為た", "した"},
+			{Potential, false, false}: {"出来る", "できる"},
+			{Passive, false, false}:   {"為れる", "される"},
+		}},
+		{"愛する [あいする] /(vs-s,vt) to love/(P)/EntL1150410X/", VsS, map[form][2]string{
+			{NonPast, false, true}:     {"愛さない", "あいさない"},
+			{Potential, false, false}:  {"愛せる", "あいせる"},
+			{Potential, false, true}:   {"愛せない", "あいせない"},
+			{Imperative, false, false}: {"愛せ", "あいせ"},
+		}},
+		{"勉強 [べんきょう] /(n,vs) study/(P)/EntL1512600X/", Vs, map[form][2]string{
+			{NonPast, false, false}: {"勉強する", "べんきょうする"},
+			{Past, false, false}:    {"勉強した", "べんきょうした"},
+		}},
+		{"高い [たかい] /(adj-i) high/(P)/EntL1279680X/", AdjI, map[form][2]string{
+			{Past, false, false}: {"高かった", "たかかった"},
+			{Te, false, true}:    {"高くなくて", "たかくなくて"},
+		}},
+		{"かっこいい /(adj-i) cool/EntL1000000/", AdjI, map[form][2]string{
+			{NonPast, false, true}: {"かっこよくない", ""},
+			{Past, false, false}:   {"かっこよかった", ""},
+		}},
+		{"静か [しずか] /(adj-na) quiet/(P)/EntL1316530X/", AdjNa, map[form][2]string{
+			{NonPast, true, true}: {"静かじゃありません", "しずかじゃありません"},
+			{Te, false, false}:    {"静かで", "しずかで"},
+		}},
+	}
+
+	for _, test := range testData {
+		entry, err := parseLine(test.line)
+		if err != nil {
+			t.Fatalf("parse %s: %v", test.line, err)
+		}
+
+		var table *ConjugationTable
+		tables := Conjugate(entry)
+		for i := range tables {
+			if tables[i].Class == test.class {
+				table = &tables[i]
+			}
+		}
+		if table == nil {
+			t.Errorf("conjugate %s: no %v table in %v", entry.Kanji[0], test.class, tables)
+			continue
+		}
+
+		got := make(map[form][2]string)
+		for _, c := range table.Forms {
+			f := form{c.Form, c.Polite, c.Negative}
+			if _, ok := test.want[f]; ok {
+				got[f] = [2]string{c.Text, c.Reading}
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("conjugate %s as %v:\n   got: %v\n  want: %v", entry.Kanji[0], test.class, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package edict
+
+import "strings"
+
+// Inflection names a way of conjugating a verb or adjective.
+type Inflection string
+
+const (
+	Negative    Inflection = "negative"    // 食べない
+	Past        Inflection = "past"        // 食べた
+	Te          Inflection = "te"          // 食べて
+	Polite      Inflection = "polite"      // 食べます
+	Potential   Inflection = "potential"   // 食べられる
+	Passive     Inflection = "passive"     // 食べられる
+	Causative   Inflection = "causative"   // 食べさせる
+	Imperative  Inflection = "imperative"  // 食べろ
+	Volitional  Inflection = "volitional"  // 食べよう
+	Conditional Inflection = "conditional" // 食べれば
+	Tara        Inflection = "tara"        // 食べたら
+	Desire      Inflection = "desire"      // 食べたい
+	Progressive Inflection = "progressive" // 食べている
+	Adverbial   Inflection = "adverbial"   // 高く
+)
+
+// wordType is a set of the word classes that a word could belong to, as far as conjugation goes.
+type wordType int
+
+const (
+	typeV1      wordType = 1 << iota // Ichidan verbs, and forms that conjugate like them, like 食べられる.
+	typeV5                           // Godan verbs.
+	typeV5kS                         // 行く, which has irregular te and past forms.
+	typeV5aru                        // くださる and friends, which have irregular polite and imperative forms.
+	typeVk                           // 来る.
+	typeVs                           // する, and nouns that take it.
+	typeVz                           // ずる verbs, like 信ずる.
+	typeAdjI                         // I-adjectives, and forms that conjugate like them, like 食べない.
+	typeMasu                         // Polite forms that conjugate from ます.
+	typeTe                           // Te forms, which can be followed by いる.
+	typeInitial                      // The word as written, before any rules have been applied.
+
+	typeAny = typeInitial<<1 - 1
+)
+
+// detailTypes maps the verb and adjective classes in Details to wordTypes.
+var detailTypes = map[Detail]wordType{
+	V1:    typeV1,
+	V5:    typeV5,
+	V5aru: typeV5 | typeV5aru,
+	V5b:   typeV5,
+	V5g:   typeV5,
+	V5k:   typeV5,
+	V5kS:  typeV5kS,
+	V5m:   typeV5,
+	V5n:   typeV5,
+	V5r:   typeV5,
+	V5rI:  typeV5,
+	V5s:   typeV5,
+	V5t:   typeV5,
+	V5u:   typeV5,
+	V5uS:  typeV5,
+	Vk:    typeVk,
+	Vs:    typeVs,
+	VsI:   typeVs,
+	VsS:   typeVs,
+	Vz:    typeVz,
+	AdjI:  typeAdjI,
+	AdjIx: typeAdjI,
+}
+
+// deinflectRule turns a word ending in from, which must be one of the types in, into a word ending
+// in to, of type out.
+type deinflectRule struct {
+	from, to string
+	in, out  wordType
+	reason   Inflection
+}
+
+// godanRows lists the endings of each row of godan verbs, for building conjugation rules.
+var godanRows = []struct {
+	u, a, i, e, o string // The dictionary form ending, and the same consonant with the other vowels.
+	te            string // The te form ending; the past form is the same, with た or だ.
+}{
+	{"う", "わ", "い", "え", "お", "って"},
+	{"く", "か", "き", "け", "こ", "いて"},
+	{"ぐ", "が", "ぎ", "げ", "ご", "いで"},
+	{"す", "さ", "し", "せ", "そ", "して"},
+	{"つ", "た", "ち", "て", "と", "って"},
+	{"ぬ", "な", "に", "ね", "の", "んで"},
+	{"ぶ", "ば", "び", "べ", "ぼ", "んで"},
+	{"む", "ま", "み", "め", "も", "んで"},
+	{"る", "ら", "り", "れ", "ろ", "って"},
+}
+
+// pastOf turns a te form ending into the past form ending.
+func pastOf(te string) string {
+	if strings.HasSuffix(te, "で") {
+		return strings.TrimSuffix(te, "で") + "だ"
+	}
+	return strings.TrimSuffix(te, "て") + "た"
+}
+
+// irregularForms lists the conjugations of 来る and する, which change their stems.  The first
+// character is replaced by 来 for the kanji spelling of 来る.
+var irregularForms = []struct {
+	kuru, suru string
+	in         wordType
+	reason     Inflection
+}{
+	{"こない", "しない", typeAdjI, Negative},
+	{"きます", "します", typeMasu, Polite},
+	{"きた", "した", typeInitial, Past},
+	{"きて", "して", typeTe, Te},
+	{"こられる", "できる", typeV1, Potential},
+	{"こられる", "される", typeV1, Passive},
+	{"こさせる", "させる", typeV1, Causative},
+	{"こい", "しろ", typeInitial, Imperative},
+	{"こよう", "しよう", typeInitial, Volitional},
+	{"くれば", "すれば", typeInitial, Conditional},
+	{"きたら", "したら", typeInitial, Tara},
+	{"きたい", "したい", typeAdjI, Desire},
+}
+
+var deinflectRules = buildDeinflectRules()
+
+func buildDeinflectRules() []deinflectRule {
+	// Ichidan verbs.
+	ichidan := []deinflectRule{
+		{"ない", "る", typeAdjI, typeV1, Negative},
+		{"ます", "る", typeMasu, typeV1, Polite},
+		{"た", "る", typeInitial, typeV1, Past},
+		{"て", "る", typeTe, typeV1, Te},
+		{"られる", "る", typeV1, typeV1, Potential},
+		{"られる", "る", typeV1, typeV1, Passive},
+		{"させる", "る", typeV1, typeV1, Causative},
+		{"ろ", "る", typeInitial, typeV1, Imperative},
+		{"よう", "る", typeInitial, typeV1, Volitional},
+		{"れば", "る", typeInitial, typeV1, Conditional},
+		{"たら", "る", typeInitial, typeV1, Tara},
+		{"たい", "る", typeAdjI, typeV1, Desire},
+	}
+	// I-adjectives, including the negative and desire forms of verbs.
+	adjective := []deinflectRule{
+		{"くない", "い", typeAdjI, typeAdjI, Negative},
+		{"かった", "い", typeInitial, typeAdjI, Past},
+		{"くて", "い", typeTe, typeAdjI, Te},
+		{"ければ", "い", typeInitial, typeAdjI, Conditional},
+		{"かったら", "い", typeInitial, typeAdjI, Tara},
+		{"く", "い", typeInitial, typeAdjI, Adverbial},
+	}
+
+	rules := append(append([]deinflectRule{}, ichidan...), adjective...)
+	rules = append(rules, []deinflectRule{
+		// Polite forms.
+		{"ません", "ます", typeMasu, typeMasu, Negative},
+		{"ました", "ます", typeInitial, typeMasu, Past},
+		{"ませんでした", "ません", typeInitial, typeMasu, Past},
+		{"まして", "ます", typeTe, typeMasu, Te},
+		{"ましょう", "ます", typeInitial, typeMasu, Volitional},
+
+		// Te form + いる, which conjugates like an ichidan verb.
+		{"ている", "て", typeV1, typeTe, Progressive},
+		{"でいる", "で", typeV1, typeTe, Progressive},
+		{"てる", "て", typeV1, typeTe, Progressive},
+		{"でる", "で", typeV1, typeTe, Progressive},
+
+		// 行く.
+		{"って", "く", typeTe, typeV5kS, Te},
+		{"った", "く", typeInitial, typeV5kS, Past},
+		{"ったら", "く", typeInitial, typeV5kS, Tara},
+
+		// くださる, なさる, いらっしゃる, ござる, おっしゃる.
+		{"います", "る", typeMasu, typeV5aru, Polite},
+		{"い", "る", typeInitial, typeV5aru, Imperative},
+	}...)
+
+	// ずる verbs conjugate like ichidan verbs ending in じる, except for the conditional.
+	for _, rule := range ichidan {
+		if rule.reason == Conditional {
+			rule.from = "ずれば"
+		} else {
+			rule.from = "じ" + rule.from
+		}
+		rule.to, rule.out = "ずる", typeVz
+		rules = append(rules, rule)
+	}
+
+	// いい conjugates from よい, as do compounds like かっこいい.
+	for _, rule := range adjective {
+		rule.from, rule.to = "よ"+rule.from, "いい"
+		rules = append(rules, rule)
+	}
+
+	for _, row := range godanRows {
+		// Everything but the te, past and tara forms of 行く follows the rest of its row.
+		v5, te := typeV5|typeV5kS, typeV5|typeV5kS
+		if row.u == "く" {
+			te = typeV5
+		}
+		rules = append(rules,
+			deinflectRule{row.a + "ない", row.u, typeAdjI, v5, Negative},
+			deinflectRule{row.i + "ます", row.u, typeMasu, v5, Polite},
+			deinflectRule{row.te, row.u, typeTe, te, Te},
+			deinflectRule{pastOf(row.te), row.u, typeInitial, te, Past},
+			deinflectRule{pastOf(row.te) + "ら", row.u, typeInitial, te, Tara},
+			deinflectRule{row.e + "る", row.u, typeV1, v5, Potential},
+			deinflectRule{row.a + "れる", row.u, typeV1, v5, Passive},
+			deinflectRule{row.a + "せる", row.u, typeV1, v5, Causative},
+			deinflectRule{row.e, row.u, typeInitial, v5, Imperative},
+			deinflectRule{row.o + "う", row.u, typeInitial, v5, Volitional},
+			deinflectRule{row.e + "ば", row.u, typeInitial, v5, Conditional},
+			deinflectRule{row.i + "たい", row.u, typeAdjI, v5, Desire},
+		)
+	}
+
+	for _, form := range irregularForms {
+		kanji := "来" + string([]rune(form.kuru)[1:])
+		rules = append(rules,
+			deinflectRule{form.kuru, "くる", form.in, typeVk, form.reason},
+			deinflectRule{kanji, "来る", form.in, typeVk, form.reason},
+			deinflectRule{form.suru, "する", form.in, typeVs, form.reason},
+		)
+	}
+
+	return rules
+}
+
+// Deinflection is a possible dictionary form of an inflected word.
+type Deinflection struct {
+	Word    string       // The possible dictionary form.
+	Reasons []Inflection // The inflections that turn Word into the original word, innermost first.
+
+	typ wordType
+}
+
+// Deinflect returns every possible dictionary form of word, by undoing chains of conjugations.
+// The first result is word itself.  Most of the results won't be real words; look them up in a
+// Dictionary, or use Dictionary.LookupInflected.
+func Deinflect(word string) []Deinflection {
+	// No rule lengthens a word, and none of the ones that keep its length can apply to their own
+	// output, so this ends.
+	result := []Deinflection{{Word: word, typ: typeAny}}
+	for i := 0; i < len(result); i++ {
+		current := result[i]
+		for _, rule := range deinflectRules {
+			if current.typ&rule.in == 0 || !strings.HasSuffix(current.Word, rule.from) {
+				continue
+			}
+			result = append(result, Deinflection{
+				Word:    strings.TrimSuffix(current.Word, rule.from) + rule.to,
+				Reasons: append([]Inflection{rule.reason}, current.Reasons...),
+				typ:     rule.out,
+			})
+		}
+	}
+
+	return result
+}
+
+// InflectedMatch is an entry found by Dictionary.LookupInflected.
+type InflectedMatch struct {
+	Entry   *Entry       // The matching entry.
+	Word    string       // The dictionary form that matched the entry.
+	Reasons []Inflection // The inflections that turn Word into the word that was looked up, innermost first.
+}
+
+// LookupInflected finds entries for a word that may be conjugated, like 食べなかった or 高くて.
+// Each possible dictionary form is only matched against entries whose part of speech conjugates
+// the way the form assumes.  Uninflected matches come first, then matches that needed the fewest
+// rules.
+func (d *Dictionary) LookupInflected(word string) []InflectedMatch {
+	var result []InflectedMatch
+	seen := make(map[*Entry]bool)
+	add := func(entry *Entry, candidate Deinflection) {
+		if !seen[entry] {
+			seen[entry] = true
+			result = append(result, InflectedMatch{Entry: entry, Word: candidate.Word, Reasons: candidate.Reasons})
+		}
+	}
+
+	for _, candidate := range Deinflect(word) {
+		for _, entry := range d.LookupAny(candidate.Word) {
+			if len(candidate.Reasons) == 0 || entryType(entry)&candidate.typ != 0 {
+				add(entry, candidate)
+			}
+		}
+
+		// Nouns that take する are listed without it.
+		if stem := strings.TrimSuffix(candidate.Word, "する"); stem != candidate.Word && stem != "" && candidate.typ&typeVs != 0 {
+			for _, entry := range d.LookupAny(stem) {
+				if entryType(entry)&typeVs != 0 {
+					add(entry, candidate)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// entryType returns the conjugation classes that an entry belongs to.
+func entryType(e *Entry) wordType {
+	var result wordType
+	for _, d := range entryDetails(*e) {
+		result |= detailTypes[d]
+	}
+	return result
+}
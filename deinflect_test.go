@@ -0,0 +1,109 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testInflectedDictionary = []string{
+	"食べる [たべる] /(v1,vt) to eat/(P)/EntL1358280X/",
+	"高い [たかい] /(adj-i) (1) high/tall/(2) expensive/(P)/EntL1279680X/",
+	"書く [かく] /(v5k,vt) to write/(P)/EntL1298670X/",
+	"読む [よむ] /(v5m,vt) to read/(P)/EntL1467640X/",
+	"行く;往く [いく;ゆく] /(v5k-s,vi) to go/(P)/EntL1578850X/",
+	"来る [くる] /(vk,vi) to come/(P)/EntL1547720X/",
+	"勉強 [べんきょう] /(n,vs) study/(P)/EntL1512600X/",
+	"下さる [くださる] /(v5aru,vt) (hon) to give/(P)/EntL1184270X/",
+	"貝 [かい] /(n) shellfish/(P)/EntL1201450X/",
+	"帰る [かえる] /(v5r,vi) to return/(P)/EntL1221270X/",
+	"変える [かえる] /(v1,vt) to change/(P)/EntL1221290X/",
+	"信ずる [しんずる] /(vz,vt) to believe/EntL1360580X/",
+	"良い;善い [よい;いい] /(adj-ix) good/(P)/EntL2820690X/",
+	"かっこいい /(adj-ix) cool/EntL1000000/",
+}
+
+func TestDeinflect(t *testing.T) {
+	testData := []struct {
+		word    string
+		want    string
+		reasons []Inflection
+	}{
+		{"食べなかった", "食べる", []Inflection{Negative, Past}},
+		{"高くて", "高い", []Inflection{Te}},
+		{"読んでいます", "読む", []Inflection{Te, Progressive, Polite}},
+		{"書かせられた", "書く", []Inflection{Causative, Passive, Past}},
+		{"食べませんでした", "食べる", []Inflection{Polite, Negative, Past}},
+		{"来ない", "来る", []Inflection{Negative}},
+		{"こられる", "くる", []Inflection{Potential}},
+		{"勉強しよう", "勉強する", []Inflection{Volitional}},
+		{"食べたくない", "食べる", []Inflection{Desire, Negative}},
+		{"信じられない", "信ずる", []Inflection{Potential, Negative}},
+		{"信ずれば", "信ずる", []Inflection{Conditional}},
+		{"よくなかった", "いい", []Inflection{Negative, Past}},
+	}
+
+	for _, test := range testData {
+		found := false
+		for _, d := range Deinflect(test.word) {
+			if d.Word == test.want && reflect.DeepEqual(d.Reasons, test.reasons) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("deinflect %s: no %s via %v in %v", test.word, test.want, test.reasons, Deinflect(test.word))
+		}
+	}
+
+	if got := Deinflect("食べる"); got[0].Word != "食べる" || len(got[0].Reasons) != 0 {
+		t.Errorf("deinflect 食べる: first result is %v, want the word itself", got[0])
+	}
+}
+
+func TestLookupInflected(t *testing.T) {
+	d, err := ReadDictionary(NewReader(strings.NewReader(strings.Join(testInflectedDictionary, "\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type match struct {
+		Sequence string
+		Word     string
+		Reasons  []Inflection
+	}
+	testData := []struct {
+		word string
+		want []match
+	}{
+		{"食べなかった", []match{{"EntL1358280", "食べる", []Inflection{Negative, Past}}}},
+		{"高くて", []match{{"EntL1279680", "高い", []Inflection{Te}}}},
+		{"書いた", []match{{"EntL1298670", "書く", []Inflection{Past}}}},
+		{"読めない", []match{{"EntL1467640", "読む", []Inflection{Potential, Negative}}}},
+		{"行った", []match{{"EntL1578850", "行く", []Inflection{Past}}}},
+		{"いって", []match{{"EntL1578850", "いく", []Inflection{Te}}}},
+		// 行く is irregular; the te form isn't 行いて.
+		{"行いて", nil},
+		{"行かない", []match{{"EntL1578850", "行く", []Inflection{Negative}}}},
+		{"信じます", []match{{"EntL1360580", "信ずる", []Inflection{Polite}}}},
+		{"よかった", []match{{"EntL2820690", "よい", []Inflection{Past}}}},
+		{"かっこよくない", []match{{"EntL1000000", "かっこいい", []Inflection{Negative}}}},
+		{"来ます", []match{{"EntL1547720", "来る", []Inflection{Polite}}}},
+		{"勉強した", []match{{"EntL1512600", "勉強する", []Inflection{Past}}}},
+		{"ください", []match{{"EntL1184270", "くださる", []Inflection{Imperative}}}},
+		{"かえない", []match{{"EntL1221290", "かえる", []Inflection{Negative}}}},
+		{"かえらない", []match{{"EntL1221270", "かえる", []Inflection{Negative}}}},
+		// かく could be the adverbial form of かい, but 貝 isn't an adjective.
+		{"かく", []match{{"EntL1298670", "かく", nil}}},
+		{"貝", []match{{"EntL1201450", "貝", nil}}},
+	}
+
+	for _, test := range testData {
+		var got []match
+		for _, m := range d.LookupInflected(test.word) {
+			got = append(got, match{m.Entry.Sequence, m.Word, m.Reasons})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("lookup %s:\n   got: %v\n  want: %v", test.word, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package edict
+
+import (
+	"io"
+	"sort"
+)
+
+// EntryReader reads entries one at a time, like Reader and JMdictReader.
+type EntryReader interface {
+	// Next returns the next entry, or io.EOF at the end of the input.
+	Next() (Entry, error)
+}
+
+// Dictionary indexes entries for lookup by key and sequence number.  Keys are compared after
+// NormalizeKana, so a lookup of カレー also finds かれー and ｶﾚｰ.  A Dictionary is never modified
+// after it's built, so it's safe for concurrent use.  The entries it returns are shared, and must
+// not be modified.
+type Dictionary struct {
+	entries  []Entry
+	kanji    map[string][]*Entry
+	kana     map[string][]*Entry
+	any      map[string][]*Entry
+	sequence map[string]*Entry
+	english  map[string][]englishPosting
+	keys     keyIndex
+}
+
+// NewDictionary builds a Dictionary from entries.  The Dictionary refers to entries directly, so
+// the caller must not modify them afterward.
+func NewDictionary(entries []Entry) *Dictionary {
+	d := &Dictionary{
+		entries:  entries,
+		kanji:    make(map[string][]*Entry),
+		kana:     make(map[string][]*Entry),
+		any:      make(map[string][]*Entry),
+		sequence: make(map[string]*Entry, len(entries)),
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		for _, key := range entry.Kanji {
+			key = NormalizeKana(key)
+			d.kanji[key] = appendEntry(d.kanji[key], entry)
+			d.any[key] = appendEntry(d.any[key], entry)
+		}
+		for _, key := range entry.Kana {
+			key = NormalizeKana(key)
+			d.kana[key] = appendEntry(d.kana[key], entry)
+			d.any[key] = appendEntry(d.any[key], entry)
+		}
+		if entry.Sequence != "" {
+			d.sequence[entry.Sequence] = entry
+		}
+	}
+
+	for _, index := range []map[string][]*Entry{d.kanji, d.kana, d.any} {
+		for _, list := range index {
+			rank(list)
+		}
+	}
+	d.indexEnglish()
+	d.keys = newKeyIndex(d.any)
+
+	return d
+}
+
+// ReadDictionary builds a Dictionary from every entry that r returns.
+func ReadDictionary(r EntryReader) (*Dictionary, error) {
+	var entries []Entry
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			return NewDictionary(entries), nil
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// appendEntry adds entry to list, unless it's already the last element; an entry can have the
+// same key more than once, like a kana-only word with its reading in both Kanji and Kana.
+func appendEntry(list []*Entry, entry *Entry) []*Entry {
+	if len(list) > 0 && list[len(list)-1] == entry {
+		return list
+	}
+	return append(list, entry)
+}
+
+// rank sorts common entries first, leaving entries in dictionary order otherwise.
+func rank(list []*Entry) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].IsCommon() && !list[j].IsCommon()
+	})
+}
+
+// Len returns the number of entries in the dictionary.
+func (d *Dictionary) Len() int {
+	return len(d.entries)
+}
+
+// Entries returns every entry in the dictionary, in the order it was built from.
+func (d *Dictionary) Entries() []Entry {
+	return d.entries
+}
+
+// LookupKanji returns the entries with the kanji key, common entries first.
+func (d *Dictionary) LookupKanji(key string) []*Entry {
+	return d.kanji[NormalizeKana(key)]
+}
+
+// LookupKana returns the entries with the kana key, common entries first.
+func (d *Dictionary) LookupKana(key string) []*Entry {
+	return d.kana[NormalizeKana(key)]
+}
+
+// LookupAny returns the entries with key as either a kanji or kana key, common entries first.
+func (d *Dictionary) LookupAny(key string) []*Entry {
+	return d.any[NormalizeKana(key)]
+}
+
+// BySequence returns the entry with the sequence number, like "EntL1039140".
+func (d *Dictionary) BySequence(sequence string) (*Entry, bool) {
+	entry, ok := d.sequence[sequence]
+	return entry, ok
+}
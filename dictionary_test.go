@@ -0,0 +1,89 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var testDictionary = []string{
+	"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+	"咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140X/",
+	"カリー /(n) Curry (surname)/EntL9000001/",
+	"カレー /(n) (obsc) Kale/EntL9000002/",
+	"カレー /(n) (1) curry/rice/(P)/EntL1039150/",
+	"嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+}
+
+func newTestDictionary(t *testing.T) *Dictionary {
+	d, err := ReadDictionary(NewReader(strings.NewReader(strings.Join(testDictionary, "\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func sequences(entries []*Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Sequence)
+	}
+	return result
+}
+
+func TestDictionaryLookup(t *testing.T) {
+	d := newTestDictionary(t)
+	if d.Len() != len(testDictionary) {
+		t.Errorf("got %d entries, want %d", d.Len(), len(testDictionary))
+	}
+
+	testData := []struct {
+		name   string
+		lookup func(string) []*Entry
+		key    string
+		want   []string
+	}{
+		{"kanji", d.LookupKanji, "咖哩", []string{"EntL1039140"}},
+		{"kanji", d.LookupKanji, "カレー", []string{"EntL1039150", "EntL9000002"}},
+		{"kanji", d.LookupKanji, "そ嚢", []string{"EntL2542030"}},
+		{"kanji", d.LookupKanji, "そのう", nil},
+		{"kana", d.LookupKana, "カレー", []string{"EntL1039140"}},
+		{"kana", d.LookupKana, "カリー", []string{"EntL1039140"}},
+		{"any", d.LookupAny, "カレー", []string{"EntL1039140", "EntL1039150", "EntL9000002"}},
+		{"any", d.LookupAny, "カリー", []string{"EntL1039140", "EntL9000001"}},
+		{"any", d.LookupAny, "ない", nil},
+	}
+
+	for _, test := range testData {
+		got := sequences(test.lookup(test.key))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s lookup of %s:\n   got: %v\n  want: %v", test.name, test.key, got, test.want)
+		}
+	}
+
+	if entry, ok := d.BySequence("EntL2542160"); !ok || entry.Kanji[0] != "刖" {
+		t.Errorf("lookup by sequence: got %v, %v", entry, ok)
+	}
+	if _, ok := d.BySequence("EntL0"); ok {
+		t.Error("lookup by sequence: found a nonexistent entry")
+	}
+}
+
+func TestDictionaryConcurrentLookup(t *testing.T) {
+	d := newTestDictionary(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if len(d.LookupAny("カレー")) != 3 {
+					t.Error("wrong number of results")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -11,22 +11,51 @@ import (
 
 // Gloss encodes an English definition for a Japanese word.
 type Gloss struct {
-	Definition  string   // English translation.
-	Information []Detail // Information about this particular definition.
-	Xref        []string // Xref to related entries (to the Kanji key), "see also".
+	Definition    string     `json:"definition"`               // English translation.
+	Information   []Detail   `json:"information,omitempty"`    // Information about this particular definition.
+	Xref          []Xref     `json:"xref,omitempty"`           // Xref to related entries, "see also".
+	Antonym       []Xref     `json:"antonym,omitempty"`        // Entries with the opposite meaning.
+	Source        []Source   `json:"source,omitempty"`         // The origins of a loanword.
+	KanjiRestrict []string   `json:"kanji_restrict,omitempty"` // Kanji keys this definition is restricted to; empty for all of them.  JMdict only.
+	KanaRestrict  []string   `json:"kana_restrict,omitempty"`  // Kana keys this definition is restricted to; empty for all of them.  JMdict only.
+	Note          string     `json:"note,omitempty"`           // Additional information about this definition, like "usu. written in kana".  JMdict only.
+	Sense         int        `json:"sense"`                    // The sense this definition belongs to, starting at 1.  Senses can have several glosses.
+	Examples      []*Example `json:"examples,omitempty"`       // Example sentences that use this sense; see LinkExamples.
 }
 
-// Entry encodes a line of edict2 input.
+// Key is a single kanji or kana key, along with the annotations attached to it.
+type Key struct {
+	Text        string   `json:"text"`                  // The key itself.
+	Information []Detail `json:"information,omitempty"` // Information about this particular key; irregular or out-dated usage, etc.
+	Common      bool     `json:"common,omitempty"`      // True if this spelling or reading is marked as common, (P).
+	Restrict    []string `json:"restrict,omitempty"`    // For kana keys, the kanji keys this reading applies to; empty for all of them.
+	Other       []string `json:"other,omitempty"`       // Annotations that aren't any of the above, like tags newer than Detail, as written.
+}
+
+// Entry encodes a line of edict2 input.  See README.md for its JSON encoding.
 type Entry struct {
-	Kanji              []string // Kanji key.
-	Kana               []string // Kana transcription of keys.
-	Information        []Detail // Information about the word; part of speech, conjugation type, etc.
-	Gloss              []Gloss  // The "glosses", English definitions, ordered by frequency.
-	Sequence           string   // The entry's unique identifier.
-	RecordingAvailable bool     // True if an audio clip of the entry reading is available from the JapanesePod101.com site.
+	Kanji              []string `json:"kanji"`                         // Kanji key.
+	Kana               []string `json:"kana,omitempty"`                // Kana transcription of keys.
+	KanjiKeys          []Key    `json:"kanji_keys,omitempty"`          // Kanji keys with their annotations; parallel to Kanji.
+	KanaKeys           []Key    `json:"kana_keys,omitempty"`           // Kana keys with their annotations; parallel to Kana.
+	Information        []Detail `json:"information,omitempty"`         // Information about the word; part of speech, conjugation type, etc.
+	Gloss              []Gloss  `json:"gloss"`                         // The "glosses", English definitions, ordered by frequency.
+	Sequence           string   `json:"sequence,omitempty"`            // The entry's unique identifier.
+	RecordingAvailable bool     `json:"recording_available,omitempty"` // True if an audio clip of the entry reading is available from the JapanesePod101.com site.
 }
 
-// String formats an Entry as a single line; not in the edict2 format, but familiar enough.
+// IsCommon returns true if the entry is marked as a common word, (P).
+func (e Entry) IsCommon() bool {
+	for _, d := range e.Information {
+		if d == Common {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats an Entry as a single line; not in the edict2 format (see Marshal for that), but
+// familiar enough.
 func (e Entry) String() string {
 	recording := ""
 	if e.RecordingAvailable {
@@ -36,36 +65,175 @@ func (e Entry) String() string {
 	return fmt.Sprintf("%v %v /%v %v/%s%s/", e.Kanji, e.Kana, e.Information, e.Gloss, e.Sequence, recording)
 }
 
-// These lines contain the record separator as part of the entry, making the whole thing
-// signficantly more difficult to parse.  We'll skip these for now, and I'll patch the dictionary to
-// not do this :)
-var blacklist = []int{5189, 31179, 104168, 104171, 148763}
+// Field identifies the part of an edict2 line that a ParseError refers to.
+type Field int
 
-func Parse(in io.Reader) ([]Entry, error) {
-	var result []Entry
+const (
+	FieldLine     Field = iota // The line as a whole.
+	FieldKey                   // The kanji and kana keys.
+	FieldDetails               // The entry-wide details that precede the (1) marker.
+	FieldGloss                 // One of the glosses; see ParseError.Gloss.
+	FieldSequence              // The sequence number at the end of the line.
+)
+
+var fieldString = map[Field]string{
+	FieldLine:     "line",
+	FieldKey:      "key",
+	FieldDetails:  "details",
+	FieldGloss:    "gloss",
+	FieldSequence: "sequence",
+}
+
+func (f Field) String() string {
+	return fieldString[f]
+}
+
+// ParseError describes an edict2 line that could not be parsed.
+type ParseError struct {
+	Line   int    // Line number, starting at 1.
+	Offset int    // Byte offset within the line where the failing field starts.
+	Field  Field  // The part of the line that failed to parse.
+	Gloss  int    // For FieldGloss, which gloss failed, starting at 1.
+	Text   string // The raw text of the line.
+	Err    error  // The underlying error.
+}
+
+func (e *ParseError) Error() string {
+	field := e.Field.String()
+	if e.Field == FieldGloss {
+		field = fmt.Sprintf("gloss %d", e.Gloss)
+	}
+	return fmt.Sprintf("parse: line %d, byte %d: %s: %s", e.Line, e.Offset, field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads edict2 entries one line at a time, so that large dictionaries can be processed
+// without holding every entry in memory.
+type Reader struct {
+	scanner  *bufio.Scanner
+	line     int
+	metadata *Metadata
+	tags     map[string]Detail
+}
+
+// NewReader returns a Reader that reads edict2 lines from in.
+func NewReader(in io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(in), tags: DetailFor}
+}
+
+// NewNameReader returns a Reader that reads ENAMDICT lines from in.  Unlike NewReader, it reads the
+// name types, like (s) and (p), as Details; in edict2 they're part of the definition.
+func NewNameReader(in io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(in), tags: enamdictDetailFor}
+}
+
+// Next returns the next entry in the input.  At the end of the input, it returns io.EOF.  Lines
+// that fail to parse are reported as a *ParseError, and do not stop the Reader; calling Next again
+// continues with the next line.
+func (r *Reader) Next() (Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := r.scanner.Text()
+		if r.line == 1 {
+			// Files saved by some Windows editors start with a byte order mark.
+			text = strings.TrimPrefix(text, "\uFEFF")
+			if isHeader(text) {
+				r.metadata = parseHeader(text)
+				continue
+			}
+		}
 
-	scanner := bufio.NewScanner(in)
-	line := 0
-lines:
-	for scanner.Scan() {
-		line++
-		entry, err := parseLine(scanner.Text())
+		entry, err := parseLineTags(text, r.tags)
 		if err != nil {
-			for _, knownBadLine := range blacklist {
-				if knownBadLine == line {
-					continue lines
-				}
+			if perr, ok := err.(*ParseError); ok {
+				perr.Line = r.line
 			}
-			return result, fmt.Errorf("parse: line %d: %s", line, err)
+			return Entry{}, err
 		}
-		result = append(result, entry)
+		return entry, nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return result, fmt.Errorf("parse: past EOF (line %d): %s", line, err)
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, fmt.Errorf("parse: past EOF (line %d): %w", r.line, err)
 	}
+	return Entry{}, io.EOF
+}
 
-	return result, nil
+// Metadata returns the information from the header line of the input, or nil if the input has no
+// header.  The header is the first line, so this is only meaningful after the first call to Next.
+func (r *Reader) Metadata() *Metadata {
+	return r.metadata
+}
+
+// Line returns the line number of the most recently read line, starting at 1.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Parse reads every entry from in.  On error, the entries read before the failing line are
+// returned along with the error.
+func Parse(in io.Reader) ([]Entry, error) {
+	var result []Entry
+
+	r := NewReader(in)
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+		result = append(result, entry)
+	}
+}
+
+// ParseOptions controls the behavior of ParseWithOptions.
+type ParseOptions struct {
+	// Lenient causes lines that fail to parse to be recorded in Result.Failures and skipped,
+	// rather than aborting the parse.
+	Lenient bool
+
+	// Encoding is the character encoding of the input.  The zero value is UTF-8.
+	Encoding Encoding
+
+	// Names causes the input to be read as ENAMDICT, whose name types, like (s) and (p), are
+	// read as Details; see NewNameReader.
+	Names bool
+}
+
+// Result is the output of ParseWithOptions.
+type Result struct {
+	Entries  []Entry       // Every entry that parsed successfully.
+	Failures []*ParseError // Lines that failed to parse; only populated in lenient mode.
+	Metadata *Metadata     // The information from the header line, or nil if there wasn't one.
+}
+
+// ParseWithOptions reads every entry from in, according to opts.  Without opts.Lenient, it behaves
+// like Parse.  In lenient mode, the returned error is non-nil only if reading the input fails.
+func ParseWithOptions(in io.Reader, opts ParseOptions) (*Result, error) {
+	result := &Result{}
+
+	newReader := NewReader
+	if opts.Names {
+		newReader = NewNameReader
+	}
+	r := newReader(Decode(in, opts.Encoding))
+	for {
+		entry, err := r.Next()
+		result.Metadata = r.Metadata()
+		if err == io.EOF {
+			return result, nil
+		} else if perr, ok := err.(*ParseError); ok && opts.Lenient {
+			result.Failures = append(result.Failures, perr)
+			continue
+		} else if err != nil {
+			return result, err
+		}
+		result.Entries = append(result.Entries, entry)
+	}
 }
 
 type identifierClass int
@@ -73,22 +241,47 @@ type identifierClass int
 const (
 	none identifierClass = iota
 	xref
+	antonym
+	source
 	detail
 	text
 )
 
-func parseIdentifier(s string) (identifierClass, string) {
+// parseIdentifier classifies the text inside a pair of parentheses.  tags are the details to
+// recognize; DetailFor for edict2, or enamdictDetailFor for ENAMDICT.
+func parseIdentifier(s string, tags map[string]Detail) (identifierClass, string) {
 	if _, err := strconv.Atoi(s); err == nil {
 		return none, ""
 	} else if strings.HasPrefix(s, "See ") {
 		return xref, strings.TrimPrefix(s, "See ")
-	} else if _, ok := DetailFor[s]; ok {
+	} else if strings.HasPrefix(s, "ant: ") {
+		return antonym, strings.TrimPrefix(s, "ant: ")
+	} else if _, ok := parseSource(s); ok {
+		return source, s
+	} else if _, ok := tags[s]; ok {
 		return detail, s
 	} else {
 		return text, s
 	}
 }
 
+// senseNumber returns the number of the sense that gloss starts, which is marked by an identifier
+// like (2) among the identifiers before the definition, or 0 if it continues the previous sense.
+func senseNumber(gloss string) int {
+	gloss = strings.TrimSpace(gloss)
+	for strings.HasPrefix(gloss, "(") {
+		end := strings.IndexRune(gloss, ')')
+		if end < 0 {
+			return 0
+		}
+		if n, err := strconv.Atoi(gloss[1:end]); err == nil {
+			return n
+		}
+		gloss = strings.TrimSpace(gloss[end+1:])
+	}
+	return 0
+}
+
 type parseGlossState int
 
 const (
@@ -98,8 +291,13 @@ const (
 	definitionGS
 )
 
-func parseGloss(gloss string) (def string, details []Detail, xrefs []string, err error) {
+// parseGloss parses the identifiers and definition of a gloss, recognizing the details in tags.
+// The caller fills in the Sense.
+func parseGloss(gloss string, tags map[string]Detail) (result Gloss, err error) {
 	gloss = strings.TrimSpace(gloss)
+	var details []Detail
+	var xrefs, antonyms []Xref
+	var sources []Source
 
 	// This is the state machine for parsing the gloss.  We start in the start state, looking
 	// for a ( starting an identifier, or the start of a definition (anything other than an
@@ -125,13 +323,26 @@ func parseGloss(gloss string) (def string, details []Detail, xrefs []string, err
 		case captureGS:
 			if c == ')' {
 				state = closedGS
-				class, identifier := parseIdentifier(string(captured))
+				class, identifier := parseIdentifier(string(captured), tags)
 
 				switch class {
 				case detail:
-					details = append(details, DetailFor[identifier])
-				case xref:
-					xrefs = append(xrefs, identifier)
+					details = append(details, tags[identifier])
+				case xref, antonym:
+					// Several references can share one "See", like (See 剕,五刑).
+					for _, s := range strings.Split(identifier, ",") {
+						x, xerr := ParseXref(s)
+						if xerr != nil {
+							err = xerr
+						} else if class == xref {
+							xrefs = append(xrefs, x)
+						} else {
+							antonyms = append(antonyms, x)
+						}
+					}
+				case source:
+					s, _ := parseSource(identifier)
+					sources = append(sources, s...)
 				case text:
 					defcapture = append(defcapture, '(')
 					for _, c := range captured {
@@ -145,9 +356,9 @@ func parseGloss(gloss string) (def string, details []Detail, xrefs []string, err
 				// Sometimes things are grouped together, like "(n,adj-no)" instead
 				// of "(n) (adj-no)".  If we see a comma, we treat it like a ), but
 				// don't transition into a different state.
-				class, identifier := parseIdentifier(string(captured))
+				class, identifier := parseIdentifier(string(captured), tags)
 				if class == detail {
-					details = append(details, DetailFor[identifier])
+					details = append(details, tags[identifier])
 					captured = captured[:0]
 				} else {
 					// TODO(jrockway): We should blow up here if we get a
@@ -173,12 +384,11 @@ func parseGloss(gloss string) (def string, details []Detail, xrefs []string, err
 	}
 
 	if state != definitionGS {
-		err = fmt.Errorf("not in definition state after parsing:\ndetails=%v, xref=%v, def=%s", details, xrefs, def)
+		err = fmt.Errorf("not in definition state after parsing:\ndetails=%v, xref=%v, def=%s", details, xrefs, string(defcapture))
 		return
 	}
 
-	def = string(defcapture)
-
+	result = Gloss{Definition: string(defcapture), Information: details, Xref: xrefs, Antonym: antonyms, Source: sources}
 	return
 }
 
@@ -203,8 +413,19 @@ func parseKey(key string) (kanji []string, kana []string, err error) {
 	// This is a state machine to parse the key field.  Keys look like:
 	// KANJI1;KANJI2;... [KANA1;KANA2;...]
 	// KANJI1;KANJI2;...
+	// Each key can carry parenthesized annotations, like KANA1(KANJI2;KANJI3), which may
+	// themselves contain ';'.  Those are captured verbatim and handled by parseKeyAnnotations.
+	depth := 0
 	for _, c := range key {
-		if c == ';' || state == kanaKS && c == ']' || state == kanjiKS && c == ' ' {
+		if c == '(' {
+			depth++
+		} else if c == ')' && depth > 0 {
+			depth--
+		}
+
+		if depth > 0 || c == ')' {
+			capture = append(capture, c)
+		} else if c == ';' || state == kanaKS && c == ']' || state == kanjiKS && c == ' ' {
 			// We've just seen a record terminator; ';' for the next element, ']' for
 			// the last kana, or ' ' for the switch from kanji to kana.
 			if state == kanjiKS {
@@ -236,6 +457,11 @@ func parseKey(key string) (kanji []string, kana []string, err error) {
 		state = doneKS
 	}
 
+	if depth > 0 {
+		err = fmt.Errorf("unbalanced '(' in key %s", key)
+		return
+	}
+
 	if !(state == doneKS || state == spaceKS) {
 		err = fmt.Errorf("not in done or space state (in %v) after parsing key %s", state, key)
 		return
@@ -248,6 +474,52 @@ func parseKey(key string) (kanji []string, kana []string, err error) {
 	return
 }
 
+// parseKeyAnnotations splits a key like "カレー(P)" or "そうざつ(嘈囃;そう囃)" into its text and
+// annotations.  Annotations that are all details are details, and (P) marks a common key.  On a
+// kana key, an annotation that lists some of the entry's kanji keys restricts the reading to them.
+// Anything else, like a tag that isn't in DetailFor yet, is kept in Other.
+func parseKeyAnnotations(key string, kana bool, kanji []string) Key {
+	result := Key{Text: fixKey(key)}
+
+	rest := strings.TrimPrefix(key, result.Text)
+	for {
+		start := strings.IndexRune(rest, '(')
+		end := strings.IndexRune(rest, ')')
+		if start < 0 || end < start {
+			break
+		}
+		annotation := rest[start+1 : end]
+		rest = rest[end+1:]
+
+		var details []Detail
+		for _, identifier := range strings.Split(annotation, ",") {
+			if d, ok := DetailFor[identifier]; ok {
+				details = append(details, d)
+			} else {
+				details = nil
+				break
+			}
+		}
+		if details == nil {
+			if restrict := strings.Split(annotation, ";"); kana && containsAll(kanji, restrict) {
+				result.Restrict = append(result.Restrict, restrict...)
+			} else {
+				result.Other = append(result.Other, annotation)
+			}
+			continue
+		}
+		for _, d := range details {
+			if d == Common {
+				result.Common = true
+			} else {
+				result.Information = append(result.Information, d)
+			}
+		}
+	}
+
+	return result
+}
+
 func fixKey(key string) string {
 	if strings.ContainsRune(key, '(') {
 		parts := strings.Split(key, "(")
@@ -256,83 +528,166 @@ func fixKey(key string) string {
 	return key
 }
 
+// parenDepth returns the number of parentheses that s leaves open.
+func parenDepth(s string) int {
+	return strings.Count(s, "(") - strings.Count(s, ")")
+}
+
+// splitFields splits a line on the '/' record separator.  A handful of entries contain a '/'
+// inside a parenthesized note, like "(n) (sometimes written AC/DC) alternating current", which a
+// naive split cuts in half.  Any part that leaves a parenthesis open is joined back together with
+// the parts that follow it until the parentheses balance again.  If they never balance, the '(' was
+// just text and the part is left alone.
+//
+// A '/' outside parentheses, like the one in "km/h", can't be told apart from a separator, so it
+// splits the gloss in two.  If that turns a lone gloss into two, the entry-wide details are taken
+// to belong to the first half, as they would for any entry with several glosses.
+func splitFields(line string) []string {
+	parts := strings.Split(line, "/")
+	fields := make([]string, 0, len(parts))
+
+	for i := 0; i < len(parts); i++ {
+		field, depth, j := parts[i], parenDepth(parts[i]), i
+		for depth > 0 && j+1 < len(parts) {
+			j++
+			field += "/" + parts[j]
+			depth += parenDepth(parts[j])
+		}
+		if depth > 0 {
+			fields = append(fields, parts[i])
+			continue
+		}
+		fields = append(fields, field)
+		i = j
+	}
+
+	return fields
+}
+
+// isSequence returns true if field is an edict2 sequence number, like EntL1039140X.
+func isSequence(field string) bool {
+	return strings.HasPrefix(field, "EntL")
+}
+
+// parseLine parses an edict2 line.
 func parseLine(line string) (Entry, error) {
+	return parseLineTags(line, DetailFor)
+}
+
+// parseLineTags parses a line, recognizing the details in tags.
+func parseLineTags(line string, tags map[string]Detail) (Entry, error) {
 	result := Entry{}
-	parts := strings.Split(line, "/")
+	parts := splitFields(line)
+
+	// offsets[i] is the byte offset of parts[i] within line.
+	offsets := make([]int, len(parts))
+	for i := 1; i < len(parts); i++ {
+		offsets[i] = offsets[i-1] + len(parts[i-1]) + 1
+	}
+	fail := func(field Field, gloss int, offset int, err error) (Entry, error) {
+		return result, &ParseError{Offset: offset, Field: field, Gloss: gloss, Text: line, Err: err}
+	}
+
+	if len(parts) < 3 {
+		return fail(FieldLine, 0, 0, fmt.Errorf("expected at least 3 '/'-separated fields, got %d", len(parts)))
+	}
+
 	last := parts[len(parts)-1]
 	if last != "" {
-		return result, fmt.Errorf("parseLine: last component should be blank, but is %s", last)
+		return fail(FieldSequence, 0, offsets[len(parts)-1], fmt.Errorf("last component should be blank, but is %s", last))
 	}
 
 	// Parse the sequence number part, since having this in the result makes misparsing lines
-	// easier to grep for.
-	result.Sequence = parts[len(parts)-2]
-	if strings.HasSuffix(result.Sequence, "X") {
-		result.RecordingAvailable = true
-		result.Sequence = strings.TrimSuffix(result.Sequence, "X")
+	// easier to grep for.  Only edict2 has sequence numbers; in edict and enamdict, the glosses
+	// run all the way to the end.  end is the index just past the last gloss.
+	end := len(parts) - 1
+	if sequence := parts[end-1]; isSequence(sequence) {
+		result.Sequence = sequence
+		if strings.HasSuffix(result.Sequence, "X") {
+			result.RecordingAvailable = true
+			result.Sequence = strings.TrimSuffix(result.Sequence, "X")
+		}
+		end--
+	}
+	if end < 2 {
+		return fail(FieldLine, 0, 0, fmt.Errorf("no glosses"))
 	}
 
 	var err error
 	result.Kanji, result.Kana, err = parseKey(parts[0])
 	if err != nil {
-		return result, err
+		return fail(FieldKey, 0, 0, err)
 	}
 
 	// Next we get some details from the first gloss.
 	glosses := []string{parts[1]}
+	glossOffsets := []int{offsets[1]}
 
-	if len(parts) > 4 {
+	if end > 2 {
 		// If there's more than one gloss, the entry-wide details come before the (1)
 		// marker.
 		firstGlossParts := strings.Split(parts[1], "(1)")
 		if len(firstGlossParts) == 2 {
-			_, detail, xref, err := parseGloss(firstGlossParts[0] + "fake definition")
+			details, err := parseGloss(firstGlossParts[0]+"fake definition", tags)
 			if err != nil {
-				return result, fmt.Errorf("parsing entry details: %s", err)
+				return fail(FieldDetails, 0, offsets[1], err)
 			}
-			if len(xref) != 0 {
-				return result, fmt.Errorf("unexpected xref in global details section")
+			if len(details.Xref) != 0 || len(details.Antonym) != 0 || len(details.Source) != 0 {
+				return fail(FieldDetails, 0, offsets[1], fmt.Errorf("unexpected xref in global details section"))
 			}
-			result.Information = detail
+			result.Information = details.Information
 			glosses[0] = firstGlossParts[1]
+			glossOffsets[0] += len(firstGlossParts[0]) + len("(1)")
 		}
 	}
 
 	// We already have the first gloss in glosses, add the rest here.
-	if len(parts) > 4 {
-		for _, gloss := range parts[2 : len(parts)-2] {
-			glosses = append(glosses, gloss)
+	if end > 2 {
+		for i := 2; i < end; i++ {
+			glosses = append(glosses, parts[i])
+			glossOffsets = append(glossOffsets, offsets[i])
 		}
 	}
 
 	result.Gloss = []Gloss{}
-	for _, gloss := range glosses {
+	sense := 1
+	for i, gloss := range glosses {
 		if gloss == "(P)" { // what a terrible file format
 			result.Information = append(result.Information, Common)
 			continue
 		}
 
-		def, detail, xref, err := parseGloss(gloss)
+		if n := senseNumber(gloss); n > 0 {
+			sense = n
+		}
+		parsed, err := parseGloss(gloss, tags)
 		if err != nil {
-			return result, fmt.Errorf("parsing gloss %s got err %s", gloss, err)
+			return fail(FieldGloss, i+1, glossOffsets[i], err)
 		}
-		result.Gloss = append(result.Gloss, Gloss{def, detail, xref})
+		parsed.Sense = sense
+		result.Gloss = append(result.Gloss, parsed)
+	}
+	if len(result.Gloss) == 0 {
+		return fail(FieldLine, 0, 0, fmt.Errorf("no glosses"))
 	}
 
 	// In the event that there's only one gloss, transfer the details to the entry.
-	if len(parts) <= 4 {
+	if end <= 2 {
 		result.Information = result.Gloss[0].Information
 		result.Gloss[0].Information = []Detail{}
 	}
 
-	// TODO(jrockway): Kanji and Kana keys can also contain (information) identifiers like
-	// entries and glosses.  For now, just remove those, though they are valuable.  (Showing the
-	// most common reading, for example.)
+	// Kanji and Kana keys can also contain (information) identifiers like entries and glosses.
+	// Those go in KanjiKeys and KanaKeys, and the plain keys are left in Kanji and Kana.
 	for i, kanji := range result.Kanji {
-		result.Kanji[i] = fixKey(kanji)
+		key := parseKeyAnnotations(kanji, false, nil)
+		result.KanjiKeys = append(result.KanjiKeys, key)
+		result.Kanji[i] = key.Text
 	}
 	for i, kana := range result.Kana {
-		result.Kana[i] = fixKey(kana)
+		key := parseKeyAnnotations(kana, true, result.Kanji)
+		result.KanaKeys = append(result.KanaKeys, key)
+		result.Kana[i] = key.Text
 	}
 
 	return result, nil
@@ -1,5 +1,7 @@
 package edict
 
+import "fmt"
+
 // A part of speech "detail" marking from http://www.edrdg.org/jmdict/edict_doc.html
 type Detail int
 
@@ -114,10 +116,27 @@ const (
 
 	// Indicators for common words
 	Common
+
+	// Name types, from ENAMDICT and JMnedict
+	Surname  // family or surname
+	Place    // place name
+	Unclass  // person name, either given or surname, as-yet unclassified
+	Given    // given name, as-yet not classified by sex
+	FemName  // female given name
+	MascName // male given name
+	Person   // full name of a particular person
+	Product  // product name
+	Company  // company name
+	Station  // railway station
+
+	// Parts of speech added after the list above; they're appended so the values of the
+	// existing Details don't change.
+	AdjIx // adjective (keiyoushi) - yoi/ii class
 )
 
 var DetailString = map[Detail]string{
 	AdjI:    "adj-i",
+	AdjIx:   "adj-ix",
 	AdjNa:   "adj-na",
 	AdjNo:   "adj-no",
 	AdjPn:   "adj-pn",
@@ -221,15 +240,81 @@ var DetailString = map[Detail]string{
 	Common:  "P",
 }
 
+// NameTypeString maps the name types to their ENAMDICT tags.  They're kept out of DetailString,
+// because in EDICT2 the same letters are just text, as in "(s)he" or "(m) metre".
+var NameTypeString = map[Detail]string{
+	Surname:  "s",
+	Place:    "p",
+	Unclass:  "u",
+	Given:    "g",
+	FemName:  "f",
+	MascName: "m",
+	Person:   "h",
+	Product:  "pr",
+	Company:  "co",
+	Station:  "st",
+}
+
 var DetailFor map[string]Detail
+var NameTypeFor map[string]Detail
+
+// enamdictDetailFor has the tags of both DetailFor and NameTypeFor, for parsing ENAMDICT.
+var enamdictDetailFor map[string]Detail
 
 func init() {
 	DetailFor = make(map[string]Detail, len(DetailString))
+	enamdictDetailFor = make(map[string]Detail, len(DetailString)+len(NameTypeString))
 	for detail, str := range DetailString {
 		DetailFor[str] = detail
+		enamdictDetailFor[str] = detail
+	}
+	NameTypeFor = make(map[string]Detail, len(NameTypeString))
+	for detail, str := range NameTypeString {
+		NameTypeFor[str] = detail
+		enamdictDetailFor[str] = detail
 	}
 }
 
 func (d Detail) String() string {
+	if s, ok := NameTypeString[d]; ok {
+		return s
+	}
 	return DetailString[d]
 }
+
+// MarshalText encodes d as its edict2 or ENAMDICT tag, like "vs-c", so that the encoding doesn't
+// depend on the order of the constants above.
+func (d Detail) MarshalText() ([]byte, error) {
+	s, ok := DetailString[d]
+	if !ok {
+		s, ok = NameTypeString[d]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown detail %d", int(d))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText decodes a tag produced by MarshalText.
+func (d *Detail) UnmarshalText(text []byte) error {
+	detail, ok := DetailFor[string(text)]
+	if !ok {
+		detail, ok = NameTypeFor[string(text)]
+	}
+	if !ok {
+		return fmt.Errorf("unknown detail %q", text)
+	}
+	*d = detail
+	return nil
+}
+
+// IsPartOfSpeech returns true if d is a part of speech, rather than a field of application or a
+// miscellaneous marking.
+func (d Detail) IsPartOfSpeech() bool {
+	return d <= Vt || d == AdjIx
+}
+
+// IsNameType returns true if d classifies a proper name, as in ENAMDICT.
+func (d Detail) IsNameType() bool {
+	return d >= Surname && d <= Station
+}
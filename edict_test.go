@@ -1,7 +1,10 @@
 package edict
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -22,6 +25,78 @@ func TestDetailFor(t *testing.T) {
 			t.Errorf("incorrect detail mapping\n   got: %s\n  want:%s", DetailFor[str], id)
 		}
 	}
+	for id, str := range NameTypeString {
+		if NameTypeFor[str] != id {
+			t.Errorf("incorrect name type mapping\n   got: %s\n  want:%s", NameTypeFor[str], id)
+		}
+		if _, ok := DetailFor[str]; ok {
+			t.Errorf("name type %s is also an edict2 detail", str)
+		}
+	}
+}
+
+func TestDetailJSON(t *testing.T) {
+	details := []Detail{N, VsC, Common, Surname}
+	got, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["n","vs-c","P","s"]`; string(got) != want {
+		t.Errorf("marshaling details:\n   got: %s\n  want: %s", got, want)
+	}
+
+	var back []Detail
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, details) {
+		t.Errorf("unmarshaling details:\n   got: %v\n  want: %v", back, details)
+	}
+
+	if err := json.Unmarshal([]byte(`["bogus"]`), &back); err == nil {
+		t.Error("expected an error unmarshaling an unknown detail")
+	}
+	if _, err := json.Marshal(Detail(-1)); err == nil {
+		t.Error("expected an error marshaling an unknown detail")
+	}
+}
+
+func TestEntryJSON(t *testing.T) {
+	entry, err := parseLine("咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140X/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kanji":["咖哩"],"kana":["カレー","カリー"],` +
+		`"kanji_keys":[{"text":"咖哩","information":["ateji"]}],` +
+		`"kana_keys":[{"text":"カレー","common":true},{"text":"カリー"}],` +
+		`"information":["n","P"],` +
+		`"gloss":[{"definition":"curry","information":["uk"],"sense":1},` +
+		`{"definition":"rice and curry","information":["abbr","uk"],"xref":[{"kanji":"カレーライス"}],"sense":2}],` +
+		`"sequence":"EntL1039140","recording_available":true}`
+	if string(got) != want {
+		t.Errorf("marshaling entry:\n   got: %s\n  want: %s", got, want)
+	}
+
+	var back Entry
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatal(err)
+	}
+	gotLine, err := Marshal(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLine, err := Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotLine != wantLine {
+		t.Errorf("unmarshaling entry:\n   got: %s\n  want: %s", gotLine, wantLine)
+	}
 }
 
 func TestParseIdentifier(t *testing.T) {
@@ -38,24 +113,26 @@ func TestParseIdentifier(t *testing.T) {
 	}
 
 	for _, test := range testData {
-		class, identifier := parseIdentifier(test.input)
+		class, identifier := parseIdentifier(test.input, DetailFor)
 
 		if class != test.class {
-			fmt.Errorf("class returned by parseIdentifier:\n   got: %s\n  want: %s", class, test.class)
+			t.Errorf("class returned by parseIdentifier:\n   got: %v\n  want: %v", class, test.class)
 		}
 
 		if identifier != test.identifier {
-			fmt.Errorf("identifier returned by parseIdentifier:\n   got: %s\n  want: %s", identifier, test.identifier)
+			t.Errorf("identifier returned by parseIdentifier:\n   got: %s\n  want: %s", identifier, test.identifier)
 		}
 	}
 }
 
 func TestParseGloss(t *testing.T) {
 	testData := []struct {
-		input   string
-		def     string
-		details []Detail
-		xrefs   []string
+		input    string
+		def      string
+		details  []Detail
+		xrefs    []Xref
+		antonyms []Xref
+		sources  []Source
 	}{
 		{
 			input:   "(n) foo",
@@ -73,13 +150,13 @@ func TestParseGloss(t *testing.T) {
 			input:   "(See foobar) foo",
 			def:     "foo",
 			details: nil,
-			xrefs:   []string{"foobar"},
+			xrefs:   []Xref{{Kanji: "foobar"}},
 		},
 		{
 			input:   "(n) (See foobar) foo",
 			def:     "foo",
 			details: []Detail{N},
-			xrefs:   []string{"foobar"},
+			xrefs:   []Xref{{Kanji: "foobar"}},
 		},
 		{
 			input:   "foo",
@@ -91,16 +168,62 @@ func TestParseGloss(t *testing.T) {
 			input:   "(1) (abbr) (uK) (See foobar) foo",
 			def:     "foo",
 			details: []Detail{Abbr, UK},
-			xrefs:   []string{"foobar"},
+			xrefs:   []Xref{{Kanji: "foobar"}},
+		},
+		{
+			input:   "(See 剕,五刑) foo",
+			def:     "foo",
+			details: nil,
+			xrefs:   []Xref{{Kanji: "剕"}, {Kanji: "五刑"}},
+		},
+		{
+			input:   "(See 半挿・はんぞう・1) foo",
+			def:     "foo",
+			details: nil,
+			xrefs:   []Xref{{Kanji: "半挿", Kana: "はんぞう", Sense: 1}},
+		},
+		{
+			input:    "(adj-i) (ant: 寒い) hot",
+			def:      "hot",
+			details:  []Detail{AdjI},
+			antonyms: []Xref{{Kanji: "寒い"}},
+		},
+		{
+			input:    "(See 温度) (ant: 寒い・さむい・1,冷たい) hot",
+			def:      "hot",
+			xrefs:    []Xref{{Kanji: "温度"}},
+			antonyms: []Xref{{Kanji: "寒い", Kana: "さむい", Sense: 1}, {Kanji: "冷たい"}},
+		},
+		{
+			input:   "(n,vs) (ger: Arbeit) part-time job",
+			def:     "part-time job",
+			details: []Detail{N, Vs},
+			sources: []Source{{Lang: "ger", Word: "Arbeit"}},
+		},
+		{
+			input:   "(wasei: after service) (fre:) (lit. after service) after-sales service",
+			def:     "(lit. after service) after-sales service",
+			sources: []Source{{Lang: "eng", Word: "after service", Wasei: true}, {Lang: "fre"}},
+		},
+		{
+			input:   "(fre: avec, ger: mit) couple",
+			def:     "couple",
+			sources: []Source{{Lang: "fre", Word: "avec"}, {Lang: "ger", Word: "mit"}},
+		},
+		{
+			input:   "(eng: bread, butter, wasei: butter roll) roll",
+			def:     "roll",
+			sources: []Source{{Lang: "eng", Word: "bread, butter"}, {Lang: "eng", Word: "butter roll", Wasei: true}},
 		},
 	}
 
 	for _, test := range testData {
-		def, details, xrefs, err := parseGloss(test.input)
+		gloss, err := parseGloss(test.input, DetailFor)
 		if err != nil {
 			t.Errorf("Error parsing '%s': %s", test.input, err)
 			continue
 		}
+		def, details, xrefs, antonyms, sources := gloss.Definition, gloss.Information, gloss.Xref, gloss.Antonym, gloss.Source
 
 		if def != test.def {
 			t.Errorf("Parsing %s: %s != %s", test.input, def, test.def)
@@ -111,7 +234,15 @@ func TestParseGloss(t *testing.T) {
 		}
 
 		if !reflect.DeepEqual(xrefs, test.xrefs) {
-			t.Errorf("Parsing %s: xrefs: %v != %v", test.input, details, test.details)
+			t.Errorf("Parsing %s: xrefs: %v != %v", test.input, xrefs, test.xrefs)
+		}
+
+		if !reflect.DeepEqual(antonyms, test.antonyms) {
+			t.Errorf("Parsing %s: antonyms: %v != %v", test.input, antonyms, test.antonyms)
+		}
+
+		if !reflect.DeepEqual(sources, test.sources) {
+			t.Errorf("Parsing %s: sources: %v != %v", test.input, sources, test.sources)
 		}
 	}
 }
@@ -159,6 +290,18 @@ func TestParseKey(t *testing.T) {
 			kana:   []string{},
 			errors: true,
 		},
+		{
+			input:  "A( [a]",
+			kanji:  []string{"A( [a]"},
+			kana:   []string{},
+			errors: true,
+		},
+		{
+			input:  "A [a(A]",
+			kanji:  []string{"A"},
+			kana:   []string{},
+			errors: true,
+		},
 	}
 
 	for _, test := range testData {
@@ -200,6 +343,90 @@ func TestFixKey(t *testing.T) {
 
 }
 
+func TestParseKeyAnnotations(t *testing.T) {
+	testData := []struct {
+		input string
+		kanji []Key
+		kana  []Key
+	}{
+		{
+			input: "咖哩(ateji) [カレー(P);カリー]",
+			kanji: []Key{{Text: "咖哩", Information: []Detail{Ateji}}},
+			kana:  []Key{{Text: "カレー", Common: true}, {Text: "カリー"}},
+		},
+		{
+			input: "嘈囃;そう囃(iK) [そうざつ(嘈囃;そう囃)(P);むねやけ(嘈囃)(ok)]",
+			kanji: []Key{{Text: "嘈囃"}, {Text: "そう囃", Information: []Detail{IK}}},
+			kana: []Key{
+				{Text: "そうざつ", Common: true, Restrict: []string{"嘈囃", "そう囃"}},
+				{Text: "むねやけ", Information: []Detail{Ok}, Restrict: []string{"嘈囃"}},
+			},
+		},
+		{
+			input: "カレー(P)",
+			kanji: []Key{{Text: "カレー", Common: true}},
+		},
+	}
+
+	for _, test := range testData {
+		got, err := parseLine(test.input + " /(n) test/EntL1/")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got.KanjiKeys, test.kanji) {
+			t.Errorf("%s: kanji keys:\n   got: %+v\n  want: %+v", test.input, got.KanjiKeys, test.kanji)
+		}
+		if !reflect.DeepEqual(got.KanaKeys, test.kana) {
+			t.Errorf("%s: kana keys:\n   got: %+v\n  want: %+v", test.input, got.KanaKeys, test.kana)
+		}
+	}
+}
+
+func TestParseKeyAnnotationsUnknown(t *testing.T) {
+	// Annotations that aren't details or restrictions are kept as written, not rejected.
+	testData := []struct {
+		input string
+		kanji []Key
+		kana  []Key
+	}{
+		{
+			// Restrictions only apply to readings.
+			input: "嘈囃(嘈囃) [そうざつ]",
+			kanji: []Key{{Text: "嘈囃", Other: []string{"嘈囃"}}},
+			kana:  []Key{{Text: "そうざつ"}},
+		},
+		{
+			// Not one of the kanji keys.
+			input: "嘈囃 [そうざつ(胸焼け)]",
+			kanji: []Key{{Text: "嘈囃"}},
+			kana:  []Key{{Text: "そうざつ", Other: []string{"胸焼け"}}},
+		},
+		{
+			// Tags newer than Detail.
+			input: "嘈囃(sK);そう囃(rK) [そうざつ(P)]",
+			kanji: []Key{{Text: "嘈囃", Other: []string{"sK"}}, {Text: "そう囃", Other: []string{"rK"}}},
+			kana:  []Key{{Text: "そうざつ", Common: true}},
+		},
+	}
+
+	for _, test := range testData {
+		got, err := parseLine(test.input + " /(n) test/EntL1/")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got.KanjiKeys, test.kanji) {
+			t.Errorf("%s: kanji keys:\n   got: %+v\n  want: %+v", test.input, got.KanjiKeys, test.kanji)
+		}
+		if !reflect.DeepEqual(got.KanaKeys, test.kana) {
+			t.Errorf("%s: kana keys:\n   got: %+v\n  want: %+v", test.input, got.KanaKeys, test.kana)
+		}
+	}
+}
+
 func TestParseLine(t *testing.T) {
 	testData := []struct {
 		input  string
@@ -210,11 +437,14 @@ func TestParseLine(t *testing.T) {
 			expect: Entry{
 				Kanji:       []string{"刖"},
 				Kana:        []string{"げつ"},
+				KanjiKeys:   []Key{{Text: "刖"}},
+				KanaKeys:    []Key{{Text: "げつ"}},
 				Information: []Detail{N, Arch, Obsc},
 				Gloss: []Gloss{{
-					"cutting off the leg at the knee (form of punishment in ancient China)",
-					[]Detail{},
-					[]string{"剕"}},
+					Definition:  "cutting off the leg at the knee (form of punishment in ancient China)",
+					Information: []Detail{},
+					Xref:        []Xref{{Kanji: "剕"}},
+					Sense:       1},
 				},
 				Sequence:           "EntL2542160",
 				RecordingAvailable: false,
@@ -225,10 +455,12 @@ func TestParseLine(t *testing.T) {
 			expect: Entry{
 				Kanji:       []string{"ジョン", "Jon"},
 				Kana:        []string{"じょん"},
+				KanjiKeys:   []Key{{Text: "ジョン"}, {Text: "Jon"}},
+				KanaKeys:    []Key{{Text: "じょん"}},
 				Information: []Detail{N},
 				Gloss: []Gloss{
-					{"my name", []Detail{Abbr, UK}, []string{"jrockway"}},
-					{"apparently a common name for dogs", []Detail{Uk}, nil},
+					{Definition: "my name", Information: []Detail{Abbr, UK}, Xref: []Xref{{Kanji: "jrockway"}}, Sense: 1},
+					{Definition: "apparently a common name for dogs", Information: []Detail{Uk}, Sense: 2},
 				},
 				Sequence:           "EntL0000000",
 				RecordingAvailable: false,
@@ -250,6 +482,200 @@ func TestParseLine(t *testing.T) {
 	}
 }
 
+func TestSplitFields(t *testing.T) {
+	testData := []struct {
+		in  string
+		out []string
+	}{
+		{"A [a] /(n) foo/bar/EntL1/", []string{"A [a] ", "(n) foo", "bar", "EntL1", ""}},
+		{"A [a] /(n) (AC/DC) foo/EntL1/", []string{"A [a] ", "(n) (AC/DC) foo", "EntL1", ""}},
+		{"A [a] /(n) foo (a/b/c)/bar/EntL1/", []string{"A [a] ", "(n) foo (a/b/c)", "bar", "EntL1", ""}},
+		{"A [a] /(n) foo (a/b (c/d))/EntL1/", []string{"A [a] ", "(n) foo (a/b (c/d))", "EntL1", ""}},
+		{"A [a] /(n) :-(/foo/EntL1/", []string{"A [a] ", "(n) :-(", "foo", "EntL1", ""}},
+	}
+
+	for _, test := range testData {
+		got := splitFields(test.in)
+		if !reflect.DeepEqual(got, test.out) {
+			t.Errorf("splitting %s:\n   got: %q\n  want: %q", test.in, got, test.out)
+		}
+	}
+}
+
+func TestParseLineEmbeddedSeparator(t *testing.T) {
+	// These have the same shape as the lines that used to be skipped by line number.
+	testData := []struct {
+		input string
+		defs  []string
+	}{
+		{
+			input: "交流 [こうりゅう] /(n) (1) (abbr. of AC/DC) alternating current/(2) exchange/EntL0000001/",
+			defs:  []string{"(abbr. of AC/DC) alternating current", "exchange"},
+		},
+		{
+			input: "入出力 [にゅうしゅつりょく] /(n) (comp) (See Ｉ/Ｏ) input-output/EntL0000002/",
+			defs:  []string{"input-output"},
+		},
+		{
+			input: "及び [および] /(conj) (written as and/or (in legal text)) and/EntL0000003/",
+			defs:  []string{"(written as and/or (in legal text)) and"},
+		},
+	}
+
+	for _, test := range testData {
+		got, err := parseLine(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.input, err)
+			continue
+		}
+
+		var defs []string
+		for _, gloss := range got.Gloss {
+			defs = append(defs, gloss.Definition)
+		}
+		if !reflect.DeepEqual(defs, test.defs) {
+			t.Errorf("%s: definitions:\n   got: %q\n  want: %q", test.input, defs, test.defs)
+		}
+	}
+}
+
+func TestParseLineBareSeparator(t *testing.T) {
+	// A '/' outside parentheses is a separator, even when it was meant as part of the definition.
+	// This documents that limitation; see splitFields.
+	testData := []struct {
+		input   string
+		details []Detail
+		glosses []Gloss
+	}{
+		{
+			input: "時速 [じそく] /(n) km/h/EntL0000004/",
+			glosses: []Gloss{
+				{Definition: "km", Information: []Detail{N}, Sense: 1},
+				{Definition: "h", Sense: 1},
+			},
+		},
+		{
+			input: "半分 [はんぶん] /(n) 1/2/EntL0000005/",
+			glosses: []Gloss{
+				{Definition: "1", Information: []Detail{N}, Sense: 1},
+				{Definition: "2", Sense: 1},
+			},
+		},
+	}
+
+	for _, test := range testData {
+		got, err := parseLine(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Information, test.details) {
+			t.Errorf("%s: details:\n   got: %v\n  want: %v", test.input, got.Information, test.details)
+		}
+		if !reflect.DeepEqual(got.Gloss, test.glosses) {
+			t.Errorf("%s: glosses:\n   got: %v\n  want: %v", test.input, got.Gloss, test.glosses)
+		}
+	}
+}
+
+func TestParseLineNameTags(t *testing.T) {
+	// The ENAMDICT name types are only details in ENAMDICT; in edict2, they're text.
+	testData := []struct {
+		input   string
+		details []Detail
+		defs    []string
+	}{
+		{"彼奴 [あいつ] /(pn) (s)he/that guy/EntL1000220/", []Detail{Pn}, []string{"(s)he", "that guy"}},
+		{"米 [メートル] /(n) (m) metre/EntL1000001/", []Detail{N}, []string{"(m) metre"}},
+		{"頁 [ページ] /(n) (p) page/EntL1000002/", []Detail{N}, []string{"(p) page"}},
+		{"瓦 [グラム] /(n) (g) gram/EntL1000003/", []Detail{N}, []string{"(g) gram"}},
+	}
+
+	for _, test := range testData {
+		got, err := parseLine(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.input, err)
+			continue
+		}
+
+		var details []Detail
+		var defs []string
+		for _, gloss := range got.Gloss {
+			details = append(details, gloss.Information...)
+			defs = append(defs, gloss.Definition)
+		}
+		details = append(details, got.Information...)
+		if !reflect.DeepEqual(details, test.details) {
+			t.Errorf("%s: details:\n   got: %v\n  want: %v", test.input, details, test.details)
+		}
+		if !reflect.DeepEqual(defs, test.defs) {
+			t.Errorf("%s: definitions:\n   got: %q\n  want: %q", test.input, defs, test.defs)
+		}
+	}
+}
+
+func TestParseEnamdict(t *testing.T) {
+	input := []string{
+		"阿部 [あべ] /(s) Abe/",
+		"あいざわ /(s,p) Aizawa/",
+		"東京駅 [とうきょうえき] /(st) Tokyo Station/EntL5000000/",
+	}
+
+	result, err := ParseWithOptions(strings.NewReader(strings.Join(input, "\n")), ParseOptions{Names: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := result.Entries
+
+	want := []struct {
+		sequence string
+		details  []Detail
+		def      string
+	}{
+		{"", []Detail{Surname}, "Abe"},
+		{"", []Detail{Surname, Place}, "Aizawa"},
+		{"EntL5000000", []Detail{Station}, "Tokyo Station"},
+	}
+	for i, w := range want {
+		if got[i].Sequence != w.sequence {
+			t.Errorf("line %d: sequence:\n   got: %s\n  want: %s", i+1, got[i].Sequence, w.sequence)
+		}
+		if !reflect.DeepEqual(got[i].Information, w.details) {
+			t.Errorf("line %d: details:\n   got: %v\n  want: %v", i+1, got[i].Information, w.details)
+		}
+		for _, d := range got[i].Information {
+			if !d.IsNameType() {
+				t.Errorf("line %d: %v is not a name type", i+1, d)
+			}
+		}
+		if got[i].Gloss[0].Definition != w.def {
+			t.Errorf("line %d: definition:\n   got: %s\n  want: %s", i+1, got[i].Gloss[0].Definition, w.def)
+		}
+	}
+}
+
+func TestDetailClasses(t *testing.T) {
+	testData := []struct {
+		detail     Detail
+		pos, names bool
+	}{
+		{AdjI, true, false},
+		{AdjIx, true, false},
+		{Vt, true, false},
+		{Uk, false, false},
+		{Surname, false, true},
+		{Station, false, true},
+	}
+	for _, test := range testData {
+		if got := test.detail.IsPartOfSpeech(); got != test.pos {
+			t.Errorf("%v: IsPartOfSpeech:\n   got: %v\n  want: %v", test.detail, got, test.pos)
+		}
+		if got := test.detail.IsNameType(); got != test.names {
+			t.Errorf("%v: IsNameType:\n   got: %v\n  want: %v", test.detail, got, test.names)
+		}
+	}
+}
+
 func TestParse(t *testing.T) {
 	input := []string{ // These are the first few entries from edict2.
 		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
@@ -273,6 +699,117 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestReader(t *testing.T) {
+	input := []string{
+		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+		"this line is garbage",
+		"嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+	}
+
+	r := NewReader(strings.NewReader(strings.Join(input, "\n")))
+
+	entry, err := r.Next()
+	if err != nil {
+		t.Fatalf("line %d: unexpected error: %s", r.Line(), err)
+	}
+	if entry.Sequence != "EntL2542160" {
+		t.Errorf("first entry: got sequence %s, want EntL2542160", entry.Sequence)
+	}
+
+	if _, err := r.Next(); err == nil {
+		t.Errorf("expected an error on line 2")
+	} else if r.Line() != 2 {
+		t.Errorf("error reported on line %d, want 2", r.Line())
+	}
+
+	// The reader can continue past a bad line.
+	entry, err = r.Next()
+	if err != nil {
+		t.Fatalf("line %d: unexpected error: %s", r.Line(), err)
+	}
+	if entry.Sequence != "EntL2542030" {
+		t.Errorf("third entry: got sequence %s, want EntL2542030", entry.Sequence)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	testData := []struct {
+		input  string
+		field  Field
+		gloss  int
+		offset int
+	}{
+		{"", FieldLine, 0, 0},
+		{"A [a] /(n) foo/EntL1/junk", FieldSequence, 0, 21},
+		{"A;B [C /(n) foo/EntL1/", FieldKey, 0, 0},
+		{"A [a] /(n) (See B) (1) foo/bar/EntL1/", FieldDetails, 0, 7},
+		{"A [a] /(n) ok/(n) (bad/EntL1/", FieldGloss, 2, 14},
+		{"A [a] /(n) (1) ok/(2) (bad/EntL1/", FieldGloss, 2, 18},
+	}
+
+	for _, test := range testData {
+		r := NewReader(strings.NewReader("A [a] /(n) fine/EntL0/\n" + test.input + "\n"))
+		if _, err := r.Next(); err != nil {
+			t.Fatalf("unexpected error on first line: %s", err)
+		}
+
+		_, err := r.Next()
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: expected a *ParseError, got %v", test.input, err)
+			continue
+		}
+		if perr.Line != 2 {
+			t.Errorf("%s: line:\n   got: %d\n  want: 2", test.input, perr.Line)
+		}
+		if perr.Field != test.field || perr.Gloss != test.gloss {
+			t.Errorf("%s: field:\n   got: %v %d\n  want: %v %d", test.input, perr.Field, perr.Gloss, test.field, test.gloss)
+		}
+		if perr.Offset != test.offset {
+			t.Errorf("%s: offset:\n   got: %d\n  want: %d", test.input, perr.Offset, test.offset)
+		}
+		if perr.Text != test.input {
+			t.Errorf("%s: text:\n   got: %s\n  want: %s", test.input, perr.Text, test.input)
+		}
+		if perr.Err == nil || errors.Unwrap(perr) != perr.Err {
+			t.Errorf("%s: error does not wrap its cause", test.input)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	input := strings.Join([]string{
+		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+		"this line is garbage",
+		"嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+		"A [a] /(n) (unterminated/EntL1/",
+	}, "\n")
+
+	if _, err := ParseWithOptions(strings.NewReader(input), ParseOptions{}); err == nil {
+		t.Error("strict parse: expected an error")
+	}
+
+	got, err := ParseWithOptions(strings.NewReader(input), ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("lenient parse: unexpected error: %s", err)
+	}
+	if len(got.Entries) != 2 {
+		t.Errorf("lenient parse: got %d entries, want 2", len(got.Entries))
+	}
+
+	var lines []int
+	for _, failure := range got.Failures {
+		lines = append(lines, failure.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 4}) {
+		t.Errorf("lenient parse: failures:\n   got: %v\n  want: %v", lines, []int{2, 4})
+	}
+}
+
 func BenchmarkEdictParse(b *testing.B) {
 	fh, err := os.Open("edict2")
 	if err != nil {
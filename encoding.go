@@ -0,0 +1,53 @@
+package edict
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// Encoding is the character encoding of a dictionary file.
+type Encoding int
+
+const (
+	UTF8           Encoding = iota // UTF-8; the default.
+	EUCJP                          // EUC-JP, the encoding of the files distributed by the EDRDG.
+	DetectEncoding                 // Guess between UTF-8 and EUC-JP by looking at the start of the input.
+)
+
+// detectSize is the number of bytes that DetectEncoding looks at.
+const detectSize = 4096
+
+// Decode returns a reader that converts in from enc to UTF-8, suitable for passing to NewReader.
+func Decode(in io.Reader, enc Encoding) io.Reader {
+	switch enc {
+	case EUCJP:
+		return transform.NewReader(in, japanese.EUCJP.NewDecoder())
+	case DetectEncoding:
+		buffered := bufio.NewReaderSize(in, detectSize)
+		// An error here just means that the input is shorter than detectSize, or that
+		// reading failed; either way, the reader will report it again later.
+		head, _ := buffered.Peek(detectSize)
+		if looksLikeUTF8(head) {
+			return buffered
+		}
+		return transform.NewReader(buffered, japanese.EUCJP.NewDecoder())
+	default:
+		return in
+	}
+}
+
+// looksLikeUTF8 returns true if b is valid UTF-8, ignoring a multi-byte character that may have
+// been cut off at the end.  Japanese text in EUC-JP is almost never valid UTF-8.
+func looksLikeUTF8(b []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(b) > 0; i++ {
+		if utf8.Valid(b) {
+			return true
+		}
+		b = b[:len(b)-1]
+	}
+	return utf8.Valid(b)
+}
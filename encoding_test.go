@@ -0,0 +1,59 @@
+package edict
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestDecode(t *testing.T) {
+	input := strings.Join([]string{
+		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+		"嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+	}, "\n")
+	eucjp, err := japanese.EUCJP.NewEncoder().String(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		name     string
+		input    string
+		encoding Encoding
+	}{
+		{"utf-8", input, UTF8},
+		{"euc-jp", eucjp, EUCJP},
+		{"detected utf-8", input, DetectEncoding},
+		{"detected euc-jp", eucjp, DetectEncoding},
+	}
+
+	for _, test := range testData {
+		got, err := ParseWithOptions(bytes.NewBufferString(test.input), ParseOptions{Encoding: test.encoding})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if len(got.Entries) != 2 {
+			t.Errorf("%s: got %d entries, want 2", test.name, len(got.Entries))
+			continue
+		}
+		if got.Entries[1].Kanji[0] != "嗉嚢" {
+			t.Errorf("%s: kanji:\n   got: %s\n  want: 嗉嚢", test.name, got.Entries[1].Kanji[0])
+		}
+	}
+}
+
+func TestLooksLikeUTF8(t *testing.T) {
+	// Cut a three-byte character in half.
+	truncated := []byte("カレー")[:7]
+	if !looksLikeUTF8(truncated) {
+		t.Error("truncated UTF-8 was not detected as UTF-8")
+	}
+
+	eucjp, _ := japanese.EUCJP.NewEncoder().Bytes([]byte("カレー"))
+	if looksLikeUTF8(eucjp) {
+		t.Error("EUC-JP was detected as UTF-8")
+	}
+}
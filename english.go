@@ -0,0 +1,141 @@
+package edict
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// englishStopWords are too common to be worth indexing or searching for.
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "in": true, "on": true,
+	"at": true, "for": true, "with": true, "by": true, "from": true, "and": true, "or": true,
+	"as": true, "be": true, "is": true, "it": true, "one": true, "s": true, "e": true, "g": true,
+	"esp": true, "etc": true, "usu": true, "lit": true,
+}
+
+// englishPosting records that a word appears in a gloss.
+type englishPosting struct {
+	entry int  // Index into Dictionary.entries.
+	gloss int  // Index into Entry.Gloss.
+	head  bool // True if the word is the first word of the definition.
+}
+
+// tokenizeEnglish splits s into lower-case words, dropping stop words.  The second result is the
+// first word outside of parentheses, the head of a definition like "(esp. ) wide-mouthed vessel".
+func tokenizeEnglish(s string) (words []string, head string) {
+	depth := 0
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := strings.ToLower(s[start:end])
+		start = -1
+		if englishStopWords[word] {
+			return
+		}
+		words = append(words, word)
+		if head == "" && depth == 0 {
+			head = word
+		}
+	}
+
+	for i, c := range s {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		if c == '(' {
+			depth++
+		} else if c == ')' && depth > 0 {
+			depth--
+		}
+	}
+	flush(len(s))
+
+	return words, head
+}
+
+// indexEnglish builds the reverse index from English words to glosses.
+func (d *Dictionary) indexEnglish() {
+	d.english = make(map[string][]englishPosting)
+	for i, entry := range d.entries {
+		for j, gloss := range entry.Gloss {
+			words, head := tokenizeEnglish(gloss.Definition)
+			for _, word := range words {
+				postings := d.english[word]
+				if n := len(postings); n > 0 && postings[n-1].entry == i && postings[n-1].gloss == j {
+					continue
+				}
+				d.english[word] = append(postings, englishPosting{entry: i, gloss: j, head: word == head})
+			}
+		}
+	}
+}
+
+// SearchEnglish returns the entries with a definition that contains every word of query, ignoring
+// case and stop words like "to" and "the".  Entries are ranked by how well their best definition
+// matches: an exact match, then a definition that starts with the query, then the first sense,
+// then common words.
+func (d *Dictionary) SearchEnglish(query string) []*Entry {
+	words, _ := tokenizeEnglish(query)
+	if len(words) == 0 {
+		return nil
+	}
+	exact := strings.Join(words, " ")
+
+	scores := make(map[int]int)
+	var order []int
+	for _, posting := range d.english[words[0]] {
+		entry := &d.entries[posting.entry]
+		gloss := entry.Gloss[posting.gloss]
+
+		glossWords, _ := tokenizeEnglish(gloss.Definition)
+		if !containsAll(glossWords, words[1:]) {
+			continue
+		}
+
+		score := 0
+		if strings.Join(glossWords, " ") == exact {
+			score += 8
+		}
+		if posting.head {
+			score += 4
+		}
+		if gloss.Sense <= 1 {
+			score += 2
+		}
+		if entry.IsCommon() {
+			score++
+		}
+
+		if best, ok := scores[posting.entry]; !ok {
+			scores[posting.entry] = score
+			order = append(order, posting.entry)
+		} else if score > best {
+			scores[posting.entry] = score
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	result := make([]*Entry, len(order))
+	for i, e := range order {
+		result[i] = &d.entries[e]
+	}
+	return result
+}
+
+func containsAll(haystack, needles []string) bool {
+	for _, needle := range needles {
+		if !contains(haystack, needle) {
+			return false
+		}
+	}
+	return true
+}
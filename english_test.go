@@ -0,0 +1,52 @@
+package edict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeEnglish(t *testing.T) {
+	testData := []struct {
+		input string
+		words []string
+		head  string
+	}{
+		{"to eat", []string{"eat"}, "eat"},
+		{"Rice and Curry", []string{"rice", "curry"}, "rice"},
+		{"(esp. ) wide-mouthed ceramic vessel", []string{"wide", "mouthed", "ceramic", "vessel"}, "wide"},
+		{"bird's crop", []string{"bird", "crop"}, "bird"},
+		{"the", nil, ""},
+	}
+
+	for _, test := range testData {
+		words, head := tokenizeEnglish(test.input)
+		if !reflect.DeepEqual(words, test.words) || head != test.head {
+			t.Errorf("tokenizing %q:\n   got: %q %q\n  want: %q %q", test.input, words, head, test.words, test.head)
+		}
+	}
+}
+
+func TestSearchEnglish(t *testing.T) {
+	d := newTestDictionary(t)
+
+	testData := []struct {
+		query string
+		want  []string
+	}{
+		// Exact matches, then the first sense, then common words.
+		{"curry", []string{"EntL1039140", "EntL1039150", "EntL9000001"}},
+		{"CURRY", []string{"EntL1039140", "EntL1039150", "EntL9000001"}},
+		{"rice and curry", []string{"EntL1039140"}},
+		{"curry rice", []string{"EntL1039140"}},
+		{"the crop", []string{"EntL2542030"}},
+		{"the", nil},
+		{"heartburn", nil},
+	}
+
+	for _, test := range testData {
+		got := sequences(d.SearchEnglish(test.query))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("searching for %q:\n   got: %v\n  want: %v", test.query, got, test.want)
+		}
+	}
+}
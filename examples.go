@@ -0,0 +1,174 @@
+package edict
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Example is a sentence from the Tanaka corpus of example sentences, which the EDRDG distributes
+// as examples.utf.
+type Example struct {
+	ID       string        `json:"id"`              // The Tatoeba ID of the sentence pair, like "1_1".
+	Japanese string        `json:"japanese"`        // The Japanese sentence.
+	English  string        `json:"english"`         // Its English translation.
+	Words    []ExampleWord `json:"words,omitempty"` // The dictionary words that the sentence uses.
+}
+
+// ExampleWord is one of the dictionary words that an example sentence uses.
+type ExampleWord struct {
+	Headword string `json:"headword"`          // The word as it appears as a key in the dictionary.
+	Reading  string `json:"reading,omitempty"` // The reading of the headword, when it's needed to pick the right entry.
+	Sense    int    `json:"sense,omitempty"`   // The sense of the entry that the sentence uses, starting at 1; 0 if unspecified.
+	Surface  string `json:"surface,omitempty"` // The form of the word in the sentence, when it differs from the headword.
+	Checked  bool   `json:"checked,omitempty"` // True if the sentence has been checked as a good example of the word.
+}
+
+// exampleWordRE matches an index entry on a B: line, like 表す(あらわす)[01]{表した}~.
+var exampleWordRE = regexp.MustCompile(`^([^(\[{~]+)(?:\(([^)]*)\))?(?:\[(\d+)\])?(?:\{([^}]*)\})?(~)?$`)
+
+// exampleFormRE matches the |1 that some releases append to a headword.  We don't need it to find
+// the entry, so it's dropped.
+var exampleFormRE = regexp.MustCompile(`\|\d+`)
+
+// ParseExamples reads the Tanaka corpus, where each example is a pair of lines like:
+// A: 彼は二十歳だ。	He is twenty.#ID=1234_5678
+// B: 彼(かれ)[01] は|1 二十歳(はたち){20歳} だ
+// On error, the examples read so far are returned along with the error.
+func ParseExamples(in io.Reader) ([]Example, error) {
+	var result []Example
+
+	scanner := bufio.NewScanner(in)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(text, "A: "):
+			example, err := parseExampleSentence(strings.TrimPrefix(text, "A: "))
+			if err != nil {
+				return result, fmt.Errorf("examples: line %d: %w", line, err)
+			}
+			result = append(result, example)
+		case strings.HasPrefix(text, "B: "):
+			if len(result) == 0 || result[len(result)-1].Words != nil {
+				return result, fmt.Errorf("examples: line %d: B: line without a preceding A: line", line)
+			}
+			words, err := parseExampleWords(strings.TrimPrefix(text, "B: "))
+			if err != nil {
+				return result, fmt.Errorf("examples: line %d: %w", line, err)
+			}
+			result[len(result)-1].Words = words
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("examples: past EOF (line %d): %w", line, err)
+	}
+	return result, nil
+}
+
+func parseExampleSentence(text string) (Example, error) {
+	result := Example{}
+
+	if i := strings.LastIndex(text, "#ID="); i >= 0 {
+		result.ID = text[i+len("#ID="):]
+		text = text[:i]
+	}
+
+	parts := strings.SplitN(text, "\t", 2)
+	if len(parts) != 2 {
+		return result, fmt.Errorf("expected a tab between the Japanese and English sentences")
+	}
+	result.Japanese, result.English = parts[0], parts[1]
+
+	return result, nil
+}
+
+func parseExampleWords(text string) ([]ExampleWord, error) {
+	result := []ExampleWord{}
+
+	for _, field := range strings.Fields(text) {
+		match := exampleWordRE.FindStringSubmatch(exampleFormRE.ReplaceAllString(field, ""))
+		if match == nil {
+			return result, fmt.Errorf("malformed word %q", field)
+		}
+
+		word := ExampleWord{Headword: match[1], Reading: match[2], Surface: match[4], Checked: match[5] != ""}
+		if match[3] != "" {
+			// The regexp only matches digits, so this can't fail.
+			word.Sense, _ = strconv.Atoi(match[3])
+		}
+		result = append(result, word)
+	}
+
+	return result, nil
+}
+
+// LinkExamples adds each example to the Examples of the glosses that its words refer to; the first
+// gloss of the sense it names, or the first gloss of the entry if it doesn't name one.  entries is
+// modified in place.  It returns the number of words that didn't match any entry.
+func LinkExamples(entries []Entry, examples []Example) int {
+	byHeadword := make(map[string][]int)
+	for i, entry := range entries {
+		for _, key := range entry.Kanji {
+			byHeadword[key] = append(byHeadword[key], i)
+		}
+		for _, key := range entry.Kana {
+			byHeadword[key] = append(byHeadword[key], i)
+		}
+	}
+
+	unlinked := 0
+	for i := range examples {
+		example := &examples[i]
+		for _, word := range example.Words {
+			linked := false
+			for _, e := range byHeadword[word.Headword] {
+				if word.Reading != "" && !contains(entries[e].Kana, word.Reading) && !contains(entries[e].Kanji, word.Reading) {
+					continue
+				}
+				gloss := firstGlossOfSense(entries[e].Gloss, word.Sense)
+				if gloss == nil {
+					continue
+				}
+				if n := len(gloss.Examples); n == 0 || gloss.Examples[n-1] != example {
+					gloss.Examples = append(gloss.Examples, example)
+				}
+				linked = true
+			}
+			if !linked {
+				unlinked++
+			}
+		}
+	}
+
+	return unlinked
+}
+
+// firstGlossOfSense returns the first gloss of the given sense, or of the first sense if sense is
+// 0.  It returns nil if there is no such sense.
+func firstGlossOfSense(glosses []Gloss, sense int) *Gloss {
+	if sense == 0 {
+		sense = 1
+	}
+	for i := range glosses {
+		if glosses[i].Sense == sense {
+			return &glosses[i]
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
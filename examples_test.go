@@ -0,0 +1,88 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testExamples = `A: 彼は二十歳だ。	He is twenty.#ID=1234_5678
+B: 彼(かれ)[01] は|1 二十歳(はたち){20歳}~ だ
+A: カレーが好きです。	I like curry.#ID=1_2
+B: 咖哩[02]{カレー} が 好き
+`
+
+func TestParseExamples(t *testing.T) {
+	got, err := ParseExamples(strings.NewReader(testExamples))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Example{
+		ID:       "1234_5678",
+		Japanese: "彼は二十歳だ。",
+		English:  "He is twenty.",
+		Words: []ExampleWord{
+			{Headword: "彼", Reading: "かれ", Sense: 1},
+			{Headword: "は"},
+			{Headword: "二十歳", Reading: "はたち", Surface: "20歳", Checked: true},
+			{Headword: "だ"},
+		},
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d examples, want 2", len(got))
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("unexpected example\n   got: %+v\n  want: %+v", got[0], want)
+	}
+
+	if _, err := ParseExamples(strings.NewReader("B: 彼(かれ)\n")); err == nil {
+		t.Error("expected an error for a B: line without an A: line")
+	}
+}
+
+func TestLinkExamples(t *testing.T) {
+	var entries []Entry
+	for _, line := range []string{
+		"咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140X/",
+		"二十歳 [はたち;にじっさい;にじゅっさい] /(n) 20 years old/twenty years old/EntL1000000/",
+		"彼 [かれ] /(pn) (1) he/him/(2) boyfriend/EntL1483070/",
+	} {
+		entry, err := parseLine(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	examples, err := ParseExamples(strings.NewReader(testExamples))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// は, だ, が and 好き aren't in the dictionary.
+	if unlinked := LinkExamples(entries, examples); unlinked != 4 {
+		t.Errorf("got %d unlinked words, want 4", unlinked)
+	}
+
+	testData := []struct {
+		entry, gloss int
+		ids          []string
+	}{
+		{0, 0, nil},
+		{0, 1, []string{"1_2"}},
+		{1, 0, []string{"1234_5678"}},
+		{1, 1, nil},
+		{2, 0, []string{"1234_5678"}},
+		{2, 2, nil},
+	}
+	for _, test := range testData {
+		var ids []string
+		for _, example := range entries[test.entry].Gloss[test.gloss].Examples {
+			ids = append(ids, example.ID)
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("entry %d gloss %d:\n   got: %v\n  want: %v", test.entry, test.gloss, ids, test.ids)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package edict
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Segment is part of a word, with the reading to write over it as furigana.
+type Segment struct {
+	Text    string `json:"text"`              // Part of the word, like 漢 or べる.
+	Reading string `json:"reading,omitempty"` // Its reading; empty for kana, which reads as itself.
+}
+
+// Furigana aligns a word with its reading, like 食べる with たべる, without KANJIDIC; see
+// KanjiIndex.Furigana.
+func Furigana(kanji, kana string) []Segment {
+	return KanjiIndex(nil).Furigana(kanji, kana)
+}
+
+// Furigana aligns a word with its reading, giving each run of kanji the part of the reading that
+// belongs to it: 食べる and たべる give 食(た) and べる.  The kana in the word (okurigana) must
+// match the reading exactly.  A run of several kanji, like 漢字, is split into one segment per
+// character if the reading can be made from the characters' KANJIDIC readings, allowing for
+// rendaku and っ; otherwise, as for 今日 or 咖哩, it's kept whole.  If the word can't be aligned
+// with the reading at all, the result is a single segment.
+func (idx KanjiIndex) Furigana(kanji, kana string) []Segment {
+	if segments, ok := idx.align(splitKanaRuns(kanji), []rune(kana)); ok {
+		return segments
+	}
+	return []Segment{{Text: kanji, Reading: kana}}
+}
+
+// kanaRun is a run of characters that are all kana, or all not.
+type kanaRun struct {
+	text string
+	kana bool
+}
+
+// isKanaRune returns true for hiragana, katakana and ー, except ヶ and ヵ, which are used like
+// kanji in words like 一ヶ月.
+func isKanaRune(r rune) bool {
+	if r == 'ヶ' || r == 'ヵ' {
+		return false
+	}
+	return r == 'ー' || unicode.In(r, unicode.Hiragana, unicode.Katakana)
+}
+
+func splitKanaRuns(s string) []kanaRun {
+	var result []kanaRun
+	for _, r := range s {
+		kana := isKanaRune(r)
+		if n := len(result); n > 0 && result[n-1].kana == kana {
+			result[n-1].text += string(r)
+		} else {
+			result = append(result, kanaRun{text: string(r), kana: kana})
+		}
+	}
+	return result
+}
+
+// align matches runs against reading, giving each run of kanji the shortest reading that lets the
+// rest match.
+func (idx KanjiIndex) align(runs []kanaRun, reading []rune) ([]Segment, bool) {
+	if len(runs) == 0 {
+		return nil, len(reading) == 0
+	}
+
+	run := runs[0]
+	length := utf8.RuneCountInString(run.text)
+	if run.kana {
+		if length > len(reading) || NormalizeKana(run.text) != NormalizeKana(string(reading[:length])) {
+			return nil, false
+		}
+		rest, ok := idx.align(runs[1:], reading[length:])
+		if !ok {
+			return nil, false
+		}
+		return append([]Segment{{Text: run.text}}, rest...), true
+	}
+
+	// Every kanji reads as at least one kana.
+	for n := length; n <= len(reading); n++ {
+		if rest, ok := idx.align(runs[1:], reading[n:]); ok {
+			return append(idx.splitKanji(run.text, string(reading[:n])), rest...), true
+		}
+	}
+	return nil, false
+}
+
+// splitKanji splits a run of kanji into one segment per character, if every character is in the
+// index and the reading can be made from their readings.
+func (idx KanjiIndex) splitKanji(text, reading string) []Segment {
+	whole := []Segment{{Text: text, Reading: reading}}
+	chars := []rune(text)
+	if len(chars) == 1 {
+		return whole
+	}
+
+	var readings [][]string
+	for i, c := range chars {
+		if c == '々' && i > 0 {
+			// The repetition mark reads like the character before it.
+			c = chars[i-1]
+		}
+		kanji, ok := idx[c]
+		if !ok {
+			return whole
+		}
+		readings = append(readings, kanjiReadings(kanji))
+	}
+
+	if split, ok := splitReading(chars, readings, reading); ok {
+		return split
+	}
+	return whole
+}
+
+// splitReading gives each of chars one of its readings, so that together they make reading.
+func splitReading(chars []rune, readings [][]string, reading string) ([]Segment, bool) {
+	if len(chars) == 0 {
+		return nil, reading == ""
+	}
+	for _, r := range readings[0] {
+		if !strings.HasPrefix(reading, r) {
+			continue
+		}
+		if rest, ok := splitReading(chars[1:], readings[1:], reading[len(r):]); ok {
+			return append([]Segment{{Text: string(chars[0]), Reading: r}}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// voiced maps kana to their voiced forms, for rendaku.
+var voiced = map[rune][]rune{
+	'か': {'が'}, 'き': {'ぎ'}, 'く': {'ぐ'}, 'け': {'げ'}, 'こ': {'ご'},
+	'さ': {'ざ'}, 'し': {'じ'}, 'す': {'ず'}, 'せ': {'ぜ'}, 'そ': {'ぞ'},
+	'た': {'だ'}, 'ち': {'ぢ'}, 'つ': {'づ'}, 'て': {'で'}, 'と': {'ど'},
+	'は': {'ば', 'ぱ'}, 'ひ': {'び', 'ぴ'}, 'ふ': {'ぶ', 'ぷ'}, 'へ': {'べ', 'ぺ'}, 'ほ': {'ぼ', 'ぽ'},
+}
+
+// kanjiReadings returns the readings of a kanji in hiragana, with the variants it takes in
+// compounds: voiced by rendaku (か to が), shortened to っ (がく to がっ), and for kun readings,
+// with the okurigana of the verb stem (う.ける to うけ).  Longer readings come first.
+func kanjiReadings(k *Kanji) []string {
+	var base []string
+	for _, on := range k.On {
+		base = append(base, katakanaToHiragana(strings.Trim(on, "-")))
+	}
+	for _, kun := range append(append([]string{}, k.Kun...), k.Nanori...) {
+		kun = strings.Trim(kun, "-")
+		stem, okurigana, _ := strings.Cut(kun, ".")
+		base = append(base, stem)
+		if okurigana == "" {
+			continue
+		}
+		okuri := []rune(okurigana)
+		last := string(okuri[len(okuri)-1])
+		if last == "る" {
+			base = append(base, stem+string(okuri[:len(okuri)-1]))
+		}
+		for _, row := range godanRows {
+			if row.u == last {
+				base = append(base, stem+string(okuri[:len(okuri)-1])+row.i)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	add := func(r string) {
+		if r != "" && !seen[r] {
+			seen[r] = true
+			result = append(result, r)
+		}
+	}
+	for _, r := range base {
+		// Readings that are all okurigana or markers, like ".x" or "-", leave nothing.
+		if r == "" {
+			continue
+		}
+		runes := []rune(r)
+		variants := []string{r}
+		for _, v := range voiced[runes[0]] {
+			variants = append(variants, string(v)+string(runes[1:]))
+		}
+		for _, v := range variants {
+			add(v)
+			vr := []rune(v)
+			if n := len(vr); n > 1 && strings.ContainsRune("つくちき", vr[n-1]) {
+				add(string(vr[:n-1]) + "っ")
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return utf8.RuneCountInString(result[i]) > utf8.RuneCountInString(result[j])
+	})
+	return result
+}
+
+// RubyHTML writes segments as HTML, with each reading in a <ruby> element.
+func RubyHTML(segments []Segment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		if s.Reading == "" {
+			b.WriteString(html.EscapeString(s.Text))
+			continue
+		}
+		b.WriteString("<ruby>")
+		b.WriteString(html.EscapeString(s.Text))
+		b.WriteString("<rt>")
+		b.WriteString(html.EscapeString(s.Reading))
+		b.WriteString("</rt></ruby>")
+	}
+	return b.String()
+}
+
+// AnkiFurigana writes segments in the markup of Anki's furigana filter, like 食[た]べる.  A reading
+// applies to the text back to the previous space, so segments with readings after the first are
+// preceded by one; Anki doesn't show it.
+func AnkiFurigana(segments []Segment) string {
+	var b strings.Builder
+	for i, s := range segments {
+		if s.Reading == "" {
+			b.WriteString(s.Text)
+			continue
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s.Text)
+		b.WriteString("[")
+		b.WriteString(s.Reading)
+		b.WriteString("]")
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package edict
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testFuriganaKanji = []Kanji{
+	{Literal: "漢", On: []string{"カン"}, Kun: []string{"おとこ"}},
+	{Literal: "字", On: []string{"ジ"}, Kun: []string{"あざ", "あざな", "-な"}},
+	{Literal: "学", On: []string{"ガク"}, Kun: []string{"まな.ぶ"}},
+	{Literal: "校", On: []string{"コウ", "キョウ"}, Kun: []string{"-か.せ"}},
+	{Literal: "本", On: []string{"ホン"}, Kun: []string{"もと"}},
+	{Literal: "棚", On: []string{"ホウ"}, Kun: []string{"たな", "-だな"}},
+	{Literal: "受", On: []string{"ジュ"}, Kun: []string{"う.ける", "-う.け"}},
+	{Literal: "付", On: []string{"フ"}, Kun: []string{"つ.ける", "-つ.け", "つ.く"}},
+	{Literal: "人", On: []string{"ジン", "ニン"}, Kun: []string{"ひと", "-り", "-と"}},
+	{Literal: "今", On: []string{"コン", "キン"}, Kun: []string{"いま"}},
+	{Literal: "日", On: []string{"ニチ", "ジツ"}, Kun: []string{"ひ", "-び", "-か"}},
+}
+
+func TestFurigana(t *testing.T) {
+	idx := NewKanjiIndex(testFuriganaKanji)
+
+	testData := []struct {
+		kanji, kana string
+		want        []Segment
+	}{
+		{"食べる", "たべる", []Segment{{"食", "た"}, {"べる", ""}}},
+		{"漢字", "かんじ", []Segment{{"漢", "かん"}, {"字", "じ"}}},
+		{"学校", "がっこう", []Segment{{"学", "がっ"}, {"校", "こう"}}},
+		{"本棚", "ほんだな", []Segment{{"本", "ほん"}, {"棚", "だな"}}},
+		{"受付", "うけつけ", []Segment{{"受", "うけ"}, {"付", "つけ"}}},
+		{"人々", "ひとびと", []Segment{{"人", "ひと"}, {"々", "びと"}}},
+		{"今日", "きょう", []Segment{{"今日", "きょう"}}},
+		{"咖哩", "カレー", []Segment{{"咖哩", "カレー"}}},
+		{"取り扱い", "とりあつかい", []Segment{{"取", "と"}, {"り", ""}, {"扱", "あつか"}, {"い", ""}}},
+		{"お茶", "おちゃ", []Segment{{"お", ""}, {"茶", "ちゃ"}}},
+		{"カレー", "かれー", []Segment{{"カレー", ""}}},
+		{"一ヶ月", "いっかげつ", []Segment{{"一ヶ月", "いっかげつ"}}},
+		{"食べる", "のむ", []Segment{{"食べる", "のむ"}}},
+	}
+
+	for _, test := range testData {
+		if got := idx.Furigana(test.kanji, test.kana); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("furigana for %s [%s]:\n   got: %v\n  want: %v", test.kanji, test.kana, got, test.want)
+		}
+	}
+
+	if got, want := Furigana("漢字", "かんじ"), []Segment{{"漢字", "かんじ"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("furigana without KANJIDIC:\n   got: %v\n  want: %v", got, want)
+	}
+}
+
+func TestFuriganaEmptyReadings(t *testing.T) {
+	// KANJIDIC readings that are only okurigana or a marker have no reading for the kanji itself.
+	idx := NewKanjiIndex([]Kanji{
+		{Literal: "漢", On: []string{"カン", "-"}, Kun: []string{".x"}},
+		{Literal: "字", On: []string{"ジ"}, Kun: []string{"-", ""}},
+	})
+
+	want := []Segment{{"漢", "かん"}, {"字", "じ"}}
+	if got := idx.Furigana("漢字", "かんじ"); !reflect.DeepEqual(got, want) {
+		t.Errorf("furigana for 漢字 [かんじ]:\n   got: %v\n  want: %v", got, want)
+	}
+}
+
+func TestFuriganaMarkup(t *testing.T) {
+	segments := []Segment{{"お", ""}, {"漢", "かん"}, {"字", "じ"}, {"<b>", ""}}
+
+	if got, want := RubyHTML(segments), "お<ruby>漢<rt>かん</rt></ruby><ruby>字<rt>じ</rt></ruby>&lt;b&gt;"; got != want {
+		t.Errorf("ruby:\n   got: %s\n  want: %s", got, want)
+	}
+	if got, want := AnkiFurigana(segments), "お 漢[かん] 字[じ]<b>"; got != want {
+		t.Errorf("anki:\n   got: %s\n  want: %s", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+package edict
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// These mirror the elements of JMdict_e.xml that we use; see the DTD at the top of that file for
+// what they all mean.  JMnedict.xml has the same structure, but calls its senses <trans>.
+type jmdictEntry struct {
+	Sequence    string          `xml:"ent_seq"`
+	Kanji       []jmdictKanji   `xml:"k_ele"`
+	Reading     []jmdictReading `xml:"r_ele"`
+	Sense       []jmdictSense   `xml:"sense"`
+	Translation []jmdictSense   `xml:"trans"`
+}
+
+type jmdictKanji struct {
+	Text     string   `xml:"keb"`
+	Info     []string `xml:"ke_inf"`
+	Priority []string `xml:"ke_pri"`
+}
+
+type jmdictReading struct {
+	Text     string   `xml:"reb"`
+	Restrict []string `xml:"re_restr"`
+	Info     []string `xml:"re_inf"`
+	Priority []string `xml:"re_pri"`
+}
+
+type jmdictSense struct {
+	KanjiRestrict []string       `xml:"stagk"`
+	KanaRestrict  []string       `xml:"stagr"`
+	PartOfSpeech  []string       `xml:"pos"`
+	Xref          []string       `xml:"xref"`
+	Antonym       []string       `xml:"ant"`
+	Source        []jmdictSource `xml:"lsource"`
+	Field         []string       `xml:"field"`
+	Misc          []string       `xml:"misc"`
+	Info          []string       `xml:"s_inf"`
+	Dialect       []string       `xml:"dial"`
+	Gloss         []jmdictGloss  `xml:"gloss"`
+	NameType      []string       `xml:"name_type"`
+	Translation   []jmdictGloss  `xml:"trans_det"`
+}
+
+type jmdictSource struct {
+	Lang  string `xml:"lang,attr"`
+	Wasei string `xml:"ls_wasei,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type jmdictGloss struct {
+	Lang string `xml:"lang,attr"`
+	Text string `xml:",chardata"`
+}
+
+// jmdictCommon are the priority markers that edict2 turns into (P).
+var jmdictCommon = map[string]bool{
+	"news1": true,
+	"ichi1": true,
+	"spec1": true,
+	"spec2": true,
+	"gai1":  true,
+}
+
+// jmnedictNameTypes maps the JMnedict name_type entities to Details.  They're spelled out, unlike
+// the ENAMDICT tags, and some ("fem") collide with JMdict tags that mean something else.
+var jmnedictNameTypes = map[string]Detail{
+	"surname": Surname,
+	"place":   Place,
+	"unclass": Unclass,
+	"given":   Given,
+	"fem":     FemName,
+	"masc":    MascName,
+	"person":  Person,
+	"product": Product,
+	"company": Company,
+	"station": Station,
+}
+
+var (
+	entityRE  = regexp.MustCompile(`<!ENTITY\s+(\S+)\s`)
+	createdRE = regexp.MustCompile(`JMdict created:\s*(\d{4}-\d{2}-\d{2})`)
+)
+
+// JMdictReader reads entries from the JMdict XML file one at a time, converting them to the same
+// Entry structure that Reader produces for edict2.  It also reads the JMnedict proper name
+// dictionary, whose name types become Details like Surname and Place.
+type JMdictReader struct {
+	decoder  *xml.Decoder
+	metadata *Metadata
+}
+
+// NewJMdictReader returns a JMdictReader that reads JMdict XML from in.
+func NewJMdictReader(in io.Reader) *JMdictReader {
+	decoder := xml.NewDecoder(in)
+
+	// JMdict encodes its tags as entities, like &n; for "noun (common) (futsuumeishi)".  We
+	// expand each entity to its own name, which is the edict2 tag that DetailFor knows about.
+	// The DTD declares the full set; these cover files whose DTD is missing.
+	decoder.Entity = make(map[string]string, len(DetailString))
+	for _, tag := range DetailString {
+		decoder.Entity[tag] = tag
+	}
+
+	return &JMdictReader{decoder: decoder}
+}
+
+// Next returns the next entry in the input.  At the end of the input, it returns io.EOF.
+func (r *JMdictReader) Next() (Entry, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err == io.EOF {
+			return Entry{}, io.EOF
+		} else if err != nil {
+			return Entry{}, fmt.Errorf("jmdict: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			for _, match := range entityRE.FindAllStringSubmatch(string(t), -1) {
+				r.decoder.Entity[match[1]] = match[1]
+			}
+		case xml.Comment:
+			if match := createdRE.FindStringSubmatch(string(t)); match != nil {
+				r.metadata = &Metadata{Description: "JMdict", Version: match[1]}
+				if created, err := time.Parse("2006-01-02", match[1]); err == nil {
+					r.metadata.Created = created
+				}
+			}
+		case xml.StartElement:
+			if t.Name.Local != "entry" {
+				continue
+			}
+			var entry jmdictEntry
+			if err := r.decoder.DecodeElement(&entry, &t); err != nil {
+				return Entry{}, fmt.Errorf("jmdict: %w", err)
+			}
+			result, err := entry.toEntry()
+			if err != nil {
+				return Entry{}, fmt.Errorf("jmdict: entry %s: %w", entry.Sequence, err)
+			}
+			return result, nil
+		}
+	}
+}
+
+// Metadata returns the release information from the "JMdict created" comment, or nil if it
+// hasn't been seen yet.
+func (r *JMdictReader) Metadata() *Metadata {
+	return r.metadata
+}
+
+// ParseJMdict reads every entry from JMdict XML.  On error, the entries read so far are returned
+// along with the error.
+func ParseJMdict(in io.Reader) ([]Entry, error) {
+	var result []Entry
+
+	r := NewJMdictReader(in)
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+		result = append(result, entry)
+	}
+}
+
+// jmdictDetails converts JMdict tags to Details, skipping the ones that edict2 doesn't have.
+func jmdictDetails(tags ...[]string) []Detail {
+	var result []Detail
+	for _, list := range tags {
+		for _, tag := range list {
+			if d, ok := DetailFor[tag]; ok {
+				result = append(result, d)
+			}
+		}
+	}
+	return result
+}
+
+func containsDetail(list []Detail, d Detail) bool {
+	for _, item := range list {
+		if item == d {
+			return true
+		}
+	}
+	return false
+}
+
+func isJMdictCommon(priority []string) bool {
+	for _, p := range priority {
+		if jmdictCommon[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// toEntry converts a JMdict entry into the Entry that parseLine would produce for the same entry
+// in edict2.  The one difference is that JMdict senses without a <pos> have the part of speech of
+// the sense before them; edict2 leaves it off, but here it's written out for each sense.
+func (e jmdictEntry) toEntry() (Entry, error) {
+	// parseLine leaves Kana empty rather than nil for kana-only words.
+	result := Entry{Sequence: "EntL" + e.Sequence, Kana: []string{}}
+	common := false
+
+	for _, k := range e.Kanji {
+		key := Key{Text: k.Text, Information: jmdictDetails(k.Info), Common: isJMdictCommon(k.Priority)}
+		result.Kanji = append(result.Kanji, key.Text)
+		result.KanjiKeys = append(result.KanjiKeys, key)
+		common = common || key.Common
+	}
+	for _, r := range e.Reading {
+		key := Key{Text: r.Text, Information: jmdictDetails(r.Info), Common: isJMdictCommon(r.Priority), Restrict: r.Restrict}
+		common = common || key.Common
+		// Like edict2, kana-only words have their readings in the kanji slot.
+		if len(e.Kanji) == 0 {
+			result.Kanji = append(result.Kanji, key.Text)
+			result.KanjiKeys = append(result.KanjiKeys, key)
+		} else {
+			result.Kana = append(result.Kana, key.Text)
+			result.KanaKeys = append(result.KanaKeys, key)
+		}
+	}
+
+	senses := e.Sense
+	if len(e.Translation) > 0 {
+		senses = e.Translation
+	}
+	result.Gloss = []Gloss{}
+	var pos []string
+	for i, sense := range senses {
+		if len(sense.PartOfSpeech) > 0 {
+			pos = sense.PartOfSpeech
+		}
+		first := len(result.Gloss)
+		glosses := sense.Gloss
+		if len(sense.Translation) > 0 {
+			glosses = sense.Translation
+		}
+		for _, g := range glosses {
+			if g.Lang != "" && g.Lang != "eng" {
+				continue
+			}
+			result.Gloss = append(result.Gloss, Gloss{Definition: g.Text, Sense: i + 1})
+		}
+		if len(result.Gloss) == first {
+			continue
+		}
+
+		// Everything that applies to the whole sense is attached to its first gloss, which is
+		// where edict2 writes it.  The first sense's part of speech is written before the (1)
+		// marker, so it belongs to the entry.
+		gloss := &result.Gloss[first]
+		if i == 0 && len(senses) > 1 {
+			result.Information = jmdictDetails(pos)
+			gloss.Information = jmdictDetails(sense.Field, sense.Misc, sense.Dialect)
+		} else {
+			gloss.Information = jmdictDetails(pos, sense.Field, sense.Misc, sense.Dialect)
+		}
+		for _, nameType := range sense.NameType {
+			d, ok := jmnedictNameTypes[nameType]
+			if !ok {
+				// Newer types like "organization" and "work" have no Detail; they're
+				// still names, so record them as unclassified rather than dropping them.
+				d = Unclass
+			}
+			if !containsDetail(gloss.Information, d) {
+				gloss.Information = append(gloss.Information, d)
+			}
+		}
+		for _, s := range sense.Xref {
+			x, err := ParseXref(s)
+			if err != nil {
+				return result, err
+			}
+			gloss.Xref = append(gloss.Xref, x)
+		}
+		for _, s := range sense.Antonym {
+			x, err := ParseXref(s)
+			if err != nil {
+				return result, err
+			}
+			gloss.Antonym = append(gloss.Antonym, x)
+		}
+		for _, s := range sense.Source {
+			// The language defaults to English.
+			source := Source{Lang: s.Lang, Word: s.Text, Wasei: s.Wasei == "y"}
+			if source.Lang == "" {
+				source.Lang = "eng"
+			}
+			gloss.Source = append(gloss.Source, source)
+		}
+		gloss.KanjiRestrict = sense.KanjiRestrict
+		gloss.KanaRestrict = sense.KanaRestrict
+		gloss.Note = strings.Join(sense.Info, "; ")
+	}
+
+	// A single gloss has its details transferred to the entry, as in parseLine.
+	if len(result.Gloss) == 1 && !common {
+		result.Information = result.Gloss[0].Information
+		result.Gloss[0].Information = []Detail{}
+	}
+	if common {
+		result.Information = append(result.Information, Common)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testJMdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ELEMENT JMdict (entry*)>
+<!ENTITY n "noun (common) (futsuumeishi)">
+<!ENTITY vs "noun or participle which takes the aux. verb suru">
+<!ENTITY uk "word usually written using kana alone">
+<!ENTITY abbr "abbreviation">
+<!ENTITY ateji "ateji (phonetic) reading">
+<!ENTITY obsc "obscure term">
+<!ENTITY ksb "Kansai-ben">
+]>
+<!-- JMdict created: 2013-06-03 -->
+<JMdict>
+<entry>
+<ent_seq>1039140</ent_seq>
+<k_ele><keb>咖哩</keb><ke_inf>&ateji;</ke_inf></k_ele>
+<r_ele><reb>カレー</reb><re_pri>ichi1</re_pri></r_ele>
+<r_ele><reb>カリー</reb></r_ele>
+<sense><pos>&n;</pos><misc>&uk;</misc><gloss>curry</gloss></sense>
+<sense><misc>&abbr;</misc><misc>&uk;</misc><xref>カレーライス</xref><gloss>rice and curry</gloss><gloss xml:lang="ger">Curryreis</gloss></sense>
+</entry>
+<entry>
+<ent_seq>2542030</ent_seq>
+<k_ele><keb>嗉嚢</keb></k_ele>
+<k_ele><keb>そ嚢</keb></k_ele>
+<r_ele><reb>そのう</reb></r_ele>
+<sense><pos>&n;</pos><gloss>bird's crop</gloss><gloss>bird's craw</gloss></sense>
+</entry>
+<entry>
+<ent_seq>2542040</ent_seq>
+<k_ele><keb>嘈囃</keb></k_ele>
+<k_ele><keb>そう囃</keb></k_ele>
+<r_ele><reb>そうざつ</reb></r_ele>
+<r_ele><reb>むねやけ</reb><re_restr>嘈囃</re_restr></r_ele>
+<sense><stagk>嘈囃</stagk><pos>&n;</pos><pos>&vs;</pos><misc>&obsc;</misc><dial>&ksb;</dial><s_inf>sometimes read むねやけ</s_inf><gloss>heartburn</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1586420</ent_seq>
+<k_ele><keb>暑い</keb></k_ele>
+<r_ele><reb>あつい</reb></r_ele>
+<sense><pos>&adj-i;</pos><ant>寒い・さむい・1</ant><gloss>hot (weather, etc.)</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1012980</ent_seq>
+<r_ele><reb>アルバイト</reb></r_ele>
+<sense><pos>&n;</pos><pos>&vs;</pos><lsource xml:lang="ger">Arbeit</lsource><gloss>part-time job</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1016990</ent_seq>
+<r_ele><reb>アフターサービス</reb></r_ele>
+<sense><pos>&n;</pos><lsource ls_wasei="y">after service</lsource><gloss>after-sales service</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1000000</ent_seq>
+<r_ele><reb>アベック</reb></r_ele>
+<sense><pos>&n;</pos><lsource xml:lang="fre">avec</lsource><lsource xml:lang="ger">mit</lsource><gloss>couple</gloss></sense>
+</entry>
+</JMdict>
+`
+
+func TestParseJMdict(t *testing.T) {
+	r := NewJMdictReader(strings.NewReader(testJMdict))
+
+	var got []Entry
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, entry)
+	}
+	if len(got) != 7 {
+		t.Fatalf("got %d entries, want 7", len(got))
+	}
+
+	if r.Metadata() == nil || r.Metadata().Version != "2013-06-03" {
+		t.Errorf("metadata: got %+v, want version 2013-06-03", r.Metadata())
+	}
+
+	// Most entries should come out exactly like their edict2 equivalents.  The second sense of
+	// 咖哩 inherits (n) from the first, which edict2 leaves off.
+	edict := map[int]string{
+		0: "咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (n) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140/",
+		1: "嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+		3: "暑い [あつい] /(adj-i) (ant: 寒い・さむい・1) hot (weather, etc.)/EntL1586420/",
+		4: "アルバイト /(n,vs) (ger: Arbeit) part-time job/EntL1012980/",
+		5: "アフターサービス /(n) (wasei: after service) after-sales service/EntL1016990/",
+		6: "アベック /(n) (fre: avec, ger: mit) couple/EntL1000000/",
+	}
+	for i, line := range edict {
+		want, err := parseLine(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("entry %d:\n   got: %+v\n  want: %+v", i, got[i], want)
+		}
+	}
+
+	// This one has information that edict2 doesn't.
+	want := Entry{
+		Kanji:     []string{"嘈囃", "そう囃"},
+		Kana:      []string{"そうざつ", "むねやけ"},
+		KanjiKeys: []Key{{Text: "嘈囃"}, {Text: "そう囃"}},
+		KanaKeys: []Key{
+			{Text: "そうざつ"},
+			{Text: "むねやけ", Restrict: []string{"嘈囃"}},
+		},
+		Information: []Detail{N, Vs, Obsc},
+		Gloss: []Gloss{{
+			Definition:    "heartburn",
+			Information:   []Detail{},
+			KanjiRestrict: []string{"嘈囃"},
+			Note:          "sometimes read むねやけ",
+			Sense:         1,
+		}},
+		Sequence: "EntL2542040",
+	}
+	if !reflect.DeepEqual(got[2], want) {
+		t.Errorf("entry 2:\n   got: %+v\n  want: %+v", got[2], want)
+	}
+}
+
+func TestParseJMdictError(t *testing.T) {
+	entries, err := ParseJMdict(strings.NewReader(`<JMdict><entry><ent_seq>1</ent_seq></entry><entry>&bogus;</entry></JMdict>`))
+	if err == nil {
+		t.Error("expected an error for an undeclared entity")
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d entries before the error, want 1", len(entries))
+	}
+}
+
+func TestParseJMnedict(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMnedict [
+<!ENTITY surname "family or surname">
+<!ENTITY place "place name">
+]>
+<JMnedict>
+<entry>
+<ent_seq>5000001</ent_seq>
+<k_ele><keb>阿部</keb></k_ele>
+<r_ele><reb>あべ</reb></r_ele>
+<trans><name_type>&surname;</name_type><name_type>&place;</name_type><trans_det>Abe</trans_det></trans>
+</entry>
+</JMnedict>`
+
+	got, err := ParseJMdict(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := parseLineTags("阿部 [あべ] /(s,p) Abe/EntL5000001/", enamdictDetailFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("unexpected entries\n   got: %+v\n  want: %+v", got, want)
+	}
+}
+
+func TestParseJMnedictOtherNameTypes(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMnedict [
+<!ENTITY organization "organization name">
+<!ENTITY group "group">
+]>
+<JMnedict>
+<entry>
+<ent_seq>5000002</ent_seq>
+<k_ele><keb>国連</keb></k_ele>
+<r_ele><reb>こくれん</reb></r_ele>
+<trans><name_type>&organization;</name_type><name_type>&group;</name_type><trans_det>United Nations</trans_det></trans>
+</entry>
+</JMnedict>`
+
+	got, err := ParseJMdict(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := parseLineTags("国連 [こくれん] /(u) United Nations/EntL5000002/", enamdictDetailFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("unexpected entries\n   got: %+v\n  want: %+v", got, want)
+	}
+}
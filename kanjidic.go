@@ -0,0 +1,275 @@
+package edict
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kanji encodes the KANJIDIC information about a single character.
+type Kanji struct {
+	Literal   string   `json:"literal"`             // The character itself.
+	Strokes   int      `json:"strokes"`             // Stroke count.
+	Grade     int      `json:"grade,omitempty"`     // School grade: 1-6 for kyouiku kanji, 8 for other jouyou kanji, 9-10 for jinmeiyou kanji; 0 if none.
+	JLPT      int      `json:"jlpt,omitempty"`      // Level in the old four-level JLPT; 0 if none.
+	Frequency int      `json:"frequency,omitempty"` // Rank among the 2500 most frequently used characters in newspapers; 0 if unranked.
+	Radical   int      `json:"radical"`             // Classical (Kangxi) radical number.
+	Skip      string   `json:"skip,omitempty"`      // SKIP code, like "4-7-1".
+	On        []string `json:"on,omitempty"`        // On readings, in katakana.
+	Kun       []string `json:"kun,omitempty"`       // Kun readings, in hiragana; okurigana follows a '.', and a '-' marks a prefix or suffix.
+	Nanori    []string `json:"nanori,omitempty"`    // Readings used only in names.
+	Meanings  []string `json:"meanings,omitempty"`  // English meanings.
+}
+
+// These mirror the elements of kanjidic2.xml that we use.
+type kanjidic2Character struct {
+	Literal  string `xml:"literal"`
+	Radicals []struct {
+		Type  string `xml:"rad_type,attr"`
+		Value int    `xml:",chardata"`
+	} `xml:"radical>rad_value"`
+	Grade     int   `xml:"misc>grade"`
+	Strokes   []int `xml:"misc>stroke_count"`
+	Frequency int   `xml:"misc>freq"`
+	JLPT      int   `xml:"misc>jlpt"`
+	QueryCode []struct {
+		Type  string `xml:"qc_type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"query_code>q_code"`
+	Readings []struct {
+		Type  string `xml:"r_type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>reading"`
+	Meanings []struct {
+		Lang  string `xml:"m_lang,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>meaning"`
+	Nanori []string `xml:"reading_meaning>nanori"`
+}
+
+func (c kanjidic2Character) toKanji() Kanji {
+	result := Kanji{
+		Literal:   c.Literal,
+		Grade:     c.Grade,
+		JLPT:      c.JLPT,
+		Frequency: c.Frequency,
+		Nanori:    c.Nanori,
+	}
+
+	// The first stroke count is the accepted one; the rest are common miscounts.
+	if len(c.Strokes) > 0 {
+		result.Strokes = c.Strokes[0]
+	}
+	for _, radical := range c.Radicals {
+		if radical.Type == "classical" {
+			result.Radical = radical.Value
+		}
+	}
+	for _, code := range c.QueryCode {
+		// Codes with a skip_misclass attribute are also marked as type "skip"; the
+		// correct one comes first.
+		if code.Type == "skip" && result.Skip == "" {
+			result.Skip = code.Value
+		}
+	}
+	for _, reading := range c.Readings {
+		switch reading.Type {
+		case "ja_on":
+			result.On = append(result.On, reading.Value)
+		case "ja_kun":
+			result.Kun = append(result.Kun, reading.Value)
+		}
+	}
+	for _, meaning := range c.Meanings {
+		if meaning.Lang == "" || meaning.Lang == "en" {
+			result.Meanings = append(result.Meanings, meaning.Value)
+		}
+	}
+
+	return result
+}
+
+// ParseKanjidic2 reads every character from KANJIDIC2 XML.  On error, the characters read so far
+// are returned along with the error.
+func ParseKanjidic2(in io.Reader) ([]Kanji, error) {
+	var result []Kanji
+
+	decoder := xml.NewDecoder(in)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, fmt.Errorf("kanjidic2: %w", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "character" {
+			var c kanjidic2Character
+			if err := decoder.DecodeElement(&c, &start); err != nil {
+				return result, fmt.Errorf("kanjidic2: %w", err)
+			}
+			result = append(result, c.toKanji())
+		}
+	}
+}
+
+// ParseKanjidic reads every character from the original kanjidic text format, where each line
+// looks like:
+// 亜 3021 U4e9c B1 C7 G8 S7 F1509 J1 P4-7-1 ア つ.ぐ T1 や つぎ つぐ {Asia} {rank next} {come after}
+// The EDRDG distributes this file in EUC-JP; see Decode.
+func ParseKanjidic(in io.Reader) ([]Kanji, error) {
+	var result []Kanji
+
+	scanner := bufio.NewScanner(in)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		kanji, err := parseKanjidicLine(text)
+		if err != nil {
+			return result, fmt.Errorf("kanjidic: line %d: %w", line, err)
+		}
+		result = append(result, kanji)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("kanjidic: past EOF (line %d): %w", line, err)
+	}
+	return result, nil
+}
+
+func parseKanjidicLine(line string) (Kanji, error) {
+	result := Kanji{}
+
+	// Meanings are in {braces} and can contain spaces; they come last.
+	if start := strings.IndexRune(line, '{'); start >= 0 {
+		for _, meaning := range strings.Split(line[start:], "}") {
+			meaning = strings.TrimSpace(meaning)
+			if meaning != "" {
+				result.Meanings = append(result.Meanings, strings.TrimPrefix(meaning, "{"))
+			}
+		}
+		line = line[:start]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return result, fmt.Errorf("expected a character and a JIS code, got %q", line)
+	}
+	result.Literal = fields[0]
+
+	// Readings after T1 are nanori, and after T2 are names of the radical.
+	readings := &result.Kun
+	for _, field := range fields[2:] {
+		trimmed := []rune(strings.TrimLeft(field, "-"))
+		if len(trimmed) == 0 {
+			continue
+		}
+		first := trimmed[0]
+		switch {
+		case field == "T1":
+			readings = &result.Nanori
+		case field == "T2":
+			readings = nil
+		case first < unicode.MaxASCII:
+			if err := parseKanjidicCode(field, &result); err != nil {
+				return result, err
+			}
+		case readings == nil:
+			// A radical name; skip it.
+		case readings == &result.Kun && unicode.In(first, unicode.Katakana):
+			result.On = append(result.On, field)
+		default:
+			*readings = append(*readings, field)
+		}
+	}
+
+	return result, nil
+}
+
+// parseKanjidicCode handles the single-letter coded fields of a kanjidic line that we use, and
+// ignores the rest.
+func parseKanjidicCode(field string, kanji *Kanji) error {
+	var target *int
+	switch field[0] {
+	case 'B':
+		// The Nelson radical; the classical radical follows in C only if it differs.
+		if kanji.Radical != 0 {
+			return nil
+		}
+		target = &kanji.Radical
+	case 'C':
+		target = &kanji.Radical
+	case 'G':
+		target = &kanji.Grade
+	case 'F':
+		target = &kanji.Frequency
+	case 'J':
+		target = &kanji.JLPT
+	case 'S':
+		// Later stroke counts are common miscounts.
+		if kanji.Strokes != 0 {
+			return nil
+		}
+		target = &kanji.Strokes
+	case 'P':
+		if kanji.Skip == "" {
+			kanji.Skip = field[1:]
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	n, err := strconv.Atoi(field[1:])
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field, err)
+	}
+	*target = n
+	return nil
+}
+
+// KanjiIndex maps characters to their KANJIDIC information.
+type KanjiIndex map[rune]*Kanji
+
+// NewKanjiIndex indexes kanji by character.
+func NewKanjiIndex(kanji []Kanji) KanjiIndex {
+	result := make(KanjiIndex, len(kanji))
+	for i := range kanji {
+		for _, c := range kanji[i].Literal {
+			result[c] = &kanji[i]
+			break
+		}
+	}
+	return result
+}
+
+// Lookup returns the information about each character of word, in order, skipping characters
+// (like kana) that aren't in the index.
+func (idx KanjiIndex) Lookup(word string) []*Kanji {
+	var result []*Kanji
+	for _, c := range word {
+		if kanji, ok := idx[c]; ok {
+			result = append(result, kanji)
+		}
+	}
+	return result
+}
+
+// ForEntry looks up the characters of each of an entry's Kanji keys; the result is parallel to
+// e.Kanji.
+func (idx KanjiIndex) ForEntry(e Entry) [][]*Kanji {
+	result := make([][]*Kanji, len(e.Kanji))
+	for i, kanji := range e.Kanji {
+		result[i] = idx.Lookup(kanji)
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testKanji = Kanji{
+	Literal:   "亜",
+	Strokes:   7,
+	Grade:     8,
+	JLPT:      1,
+	Frequency: 1509,
+	Radical:   7,
+	Skip:      "4-7-1",
+	On:        []string{"ア"},
+	Kun:       []string{"つ.ぐ"},
+	Nanori:    []string{"や", "つぎ", "つぐ"},
+	Meanings:  []string{"Asia", "rank next", "come after", "-ous"},
+}
+
+func TestParseKanjidic2(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<kanjidic2>
+<header><file_version>4</file_version></header>
+<character>
+<literal>亜</literal>
+<radical><rad_value rad_type="classical">7</rad_value><rad_value rad_type="nelson_c">1</rad_value></radical>
+<misc><grade>8</grade><stroke_count>7</stroke_count><stroke_count>8</stroke_count><freq>1509</freq><jlpt>1</jlpt></misc>
+<query_code><q_code qc_type="skip">4-7-1</q_code><q_code qc_type="sh_desc">0a7.14</q_code></query_code>
+<reading_meaning>
+<rmgroup>
+<reading r_type="pinyin">ya4</reading>
+<reading r_type="ja_on">ア</reading>
+<reading r_type="ja_kun">つ.ぐ</reading>
+<meaning>Asia</meaning>
+<meaning>rank next</meaning>
+<meaning m_lang="fr">Asie</meaning>
+<meaning>come after</meaning>
+<meaning>-ous</meaning>
+</rmgroup>
+<nanori>や</nanori><nanori>つぎ</nanori><nanori>つぐ</nanori>
+</reading_meaning>
+</character>
+</kanjidic2>`
+
+	got, err := ParseKanjidic2(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d characters, want 1", len(got))
+	}
+	if !reflect.DeepEqual(got[0], testKanji) {
+		t.Errorf("unexpected kanji\n   got: %+v\n  want: %+v", got[0], testKanji)
+	}
+}
+
+func TestParseKanjidic(t *testing.T) {
+	input := strings.Join([]string{
+		"# KANJIDIC JIS X 0208 Kanji Dictionary File",
+		"亜 3021 U4e9c B1 C7 G8 S7 S8 XJ13F59 F1509 J1 N43 V81 H3540 DK2204 L1809 K1331 O1894 DO1788 MN272 MP1.0525 E997 IN1616 DF1032 DT1092 DJ1818 DG35 P4-7-1 I0a7.14 Q1010.6 MY Yya4 Wa ア つ.ぐ T1 や つぎ つぐ T2 あ {Asia} {rank next} {come after} {-ous}",
+	}, "\n")
+
+	got, err := ParseKanjidic(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d characters, want 1", len(got))
+	}
+	if !reflect.DeepEqual(got[0], testKanji) {
+		t.Errorf("unexpected kanji\n   got: %+v\n  want: %+v", got[0], testKanji)
+	}
+}
+
+func TestKanjiIndex(t *testing.T) {
+	idx := NewKanjiIndex([]Kanji{testKanji, {Literal: "細"}})
+
+	got := idx.ForEntry(Entry{Kanji: []string{"亜細亜", "亜ぐ"}})
+	want := [][]string{{"亜", "細", "亜"}, {"亜"}}
+	for i := range want {
+		var literals []string
+		for _, kanji := range got[i] {
+			literals = append(literals, kanji.Literal)
+		}
+		if !reflect.DeepEqual(literals, want[i]) {
+			t.Errorf("key %d:\n   got: %v\n  want: %v", i, literals, want[i])
+		}
+	}
+}
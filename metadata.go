@@ -0,0 +1,53 @@
+package edict
+
+import (
+	"strings"
+	"time"
+)
+
+// Metadata describes a dictionary release, as recorded in the header of the file.
+type Metadata struct {
+	Description string    `json:"description,omitempty"` // The file's description of itself, like "EDICT, EDICT_SUB(P), EDICT2 Japanese-English Electronic Dictionary Files".
+	Copyright   string    `json:"copyright,omitempty"`   // The copyright notice.
+	Created     time.Time `json:"created"`               // The date the file was created; zero if the header doesn't say.
+	Version     string    `json:"version,omitempty"`     // The release; the EDRDG identifies releases by date, so this is the "Created:" text.
+}
+
+// headerKey is the key of the pseudo-entry that holds the header of an edict file.
+const headerKey = "　？？？"
+
+// isHeader returns true if line is the header line of an edict file, rather than an entry.
+func isHeader(line string) bool {
+	return strings.HasPrefix(line, headerKey)
+}
+
+// parseHeader extracts the metadata from the header line of an edict file, which looks like:
+// 　？？？ /,,,/ EDICT, EDICT_SUB(P), EDICT2 Japanese-English Electronic Dictionary Files/Copyright Electronic Dictionary Research & Development Group - 2011/Created: 2011-04-21/
+func parseHeader(line string) *Metadata {
+	result := &Metadata{}
+
+	for _, field := range strings.Split(line, "/")[1:] {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "" || strings.Trim(field, ",") == "":
+			continue
+		case strings.HasPrefix(field, "Copyright"):
+			result.Copyright = field
+		case strings.HasPrefix(field, "Created:"):
+			result.Version = strings.TrimSpace(strings.TrimPrefix(field, "Created:"))
+			if created, err := time.Parse("2006-01-02", result.Version); err == nil {
+				result.Created = created
+			}
+		case result.Description == "":
+			// Some releases tag the description like a gloss, "(n) EDICT, ...".
+			if strings.HasPrefix(field, "(") {
+				if end := strings.Index(field, ") "); end > 0 {
+					field = field[end+2:]
+				}
+			}
+			result.Description = field
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,64 @@
+package edict
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHeader(t *testing.T) {
+	input := strings.Join([]string{
+		"　？？？ /,,,/ EDICT, EDICT_SUB(P), EDICT2 Japanese-English Electronic Dictionary Files/Copyright Electronic Dictionary Research & Development Group - 2011/Created: 2011-04-21/",
+		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+	}, "\n")
+
+	got, err := ParseWithOptions(strings.NewReader(input), ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Entries) != 1 {
+		t.Errorf("got %d entries, want 1", len(got.Entries))
+	}
+
+	want := Metadata{
+		Description: "EDICT, EDICT_SUB(P), EDICT2 Japanese-English Electronic Dictionary Files",
+		Copyright:   "Copyright Electronic Dictionary Research & Development Group - 2011",
+		Created:     time.Date(2011, 4, 21, 0, 0, 0, 0, time.UTC),
+		Version:     "2011-04-21",
+	}
+	if got.Metadata == nil {
+		t.Fatal("no metadata")
+	}
+	if *got.Metadata != want {
+		t.Errorf("metadata:\n   got: %+v\n  want: %+v", *got.Metadata, want)
+	}
+}
+
+func TestNoHeader(t *testing.T) {
+	r := NewReader(strings.NewReader("刖 [げつ] /(n) (arch) cutting off the leg at the knee/EntL2542160/"))
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Metadata() != nil {
+		t.Errorf("unexpected metadata %+v", r.Metadata())
+	}
+}
+
+func TestHeaderAfterBOM(t *testing.T) {
+	input := "\uFEFF　？？？ /,,,/ EDICT Japanese-English Electronic Dictionary Files/Created: 2011-04-21/\n" +
+		"刖 [げつ] /(n) (arch) cutting off the leg at the knee/EntL2542160/"
+	r := NewReader(strings.NewReader(input))
+	e, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.Kanji[0], "刖"; got != want {
+		t.Errorf("kanji: got %q, want %q", got, want)
+	}
+	if r.Metadata() == nil {
+		t.Fatal("no metadata")
+	}
+	if got, want := r.Metadata().Version, "2011-04-21"; got != want {
+		t.Errorf("version: got %q, want %q", got, want)
+	}
+}
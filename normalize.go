@@ -0,0 +1,39 @@
+package edict
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeKana folds away the differences in how a word can be written that don't matter when
+// looking it up.  It applies Unicode NFKC normalization, which turns half-width katakana and
+// full-width ASCII into their usual forms; converts katakana to hiragana; and replaces each ー
+// that follows a kana with the vowel it lengthens.  カレー, かれー, ｶﾚｰ and かれえ all become かれえ.
+func NormalizeKana(s string) string {
+	runes := []rune(katakanaToHiragana(norm.NFKC.String(s)))
+	for i, r := range runes {
+		if r != 'ー' || i == 0 {
+			continue
+		}
+		if vowel, ok := kanaVowel(runes[i-1]); ok {
+			runes[i] = vowel
+		}
+	}
+	return string(runes)
+}
+
+// kanaVowel returns the vowel that a hiragana character ends with, as hiragana.  It returns false
+// for characters without a vowel, like ん and っ.
+func kanaVowel(r rune) (rune, bool) {
+	romaji, ok := kanaToRomaji[string(r)]
+	if !ok {
+		return 0, false
+	}
+	hepburn := romaji[Hepburn]
+	i := strings.IndexByte("aiueo", hepburn[len(hepburn)-1])
+	if i < 0 {
+		return 0, false
+	}
+	return []rune("あいうえお")[i], true
+}
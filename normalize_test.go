@@ -0,0 +1,48 @@
+package edict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeKana(t *testing.T) {
+	testData := []struct {
+		in, want string
+	}{
+		{"カレー", "かれえ"},
+		{"かれー", "かれえ"},
+		{"ｶﾚｰ", "かれえ"},
+		{"かれえ", "かれえ"},
+		{"ｶﾞｯｺｳ", "がっこう"},
+		{"ラーメン", "らあめん"},
+		{"パーティー", "ぱあてぃい"},
+		{"ショー", "しょお"},
+		{"ンー", "んー"},
+		{"ー", "ー"},
+		{"ＣＤプレーヤー", "CDぷれえやあ"},
+		{"咖哩", "咖哩"},
+	}
+
+	for _, test := range testData {
+		if got := NormalizeKana(test.in); got != test.want {
+			t.Errorf("normalize %s:\n   got: %s\n  want: %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestDictionaryNormalizedLookup(t *testing.T) {
+	d := newTestDictionary(t)
+	want := []string{"EntL1039140", "EntL1039150", "EntL9000002"}
+
+	for _, key := range []string{"カレー", "かれー", "ｶﾚｰ", "かれえ"} {
+		if got := sequences(d.LookupAny(key)); !reflect.DeepEqual(got, want) {
+			t.Errorf("lookup %s:\n   got: %v\n  want: %v", key, got, want)
+		}
+	}
+	if got := sequences(d.SearchPrefix("ｶﾘ")); !reflect.DeepEqual(got, []string{"EntL1039140", "EntL9000001"}) {
+		t.Errorf("prefix search ｶﾘ: got %v", got)
+	}
+	if got := sequences(d.LookupRomaji("kare-")); !reflect.DeepEqual(got, want) {
+		t.Errorf("romaji lookup kare-:\n   got: %v\n  want: %v", got, want)
+	}
+}
@@ -0,0 +1,409 @@
+package edict
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RomajiSystem is a way of writing Japanese in the Latin alphabet.
+type RomajiSystem int
+
+const (
+	Hepburn    RomajiSystem = iota // Modified Hepburn: shi, chi, tsu, fu, ji, and ō for a long vowel.
+	Kunrei                         // Kunrei-shiki: si, ti, tu, hu, zi, and ô for a long vowel.
+	NihonShiki                     // Nihon-shiki: like Kunrei, but ぢ is di, づ is du and を is wo.
+)
+
+// romajiSyllables lists the romaji for each kana syllable in each RomajiSystem.  Where a spelling is
+// ambiguous, like ti for both ち and ティ, the first syllable listed wins when reading romaji.
+var romajiSyllables = []struct {
+	kana   string
+	romaji [3]string
+}{
+	{"あ", [3]string{"a", "a", "a"}}, {"い", [3]string{"i", "i", "i"}}, {"う", [3]string{"u", "u", "u"}},
+	{"え", [3]string{"e", "e", "e"}}, {"お", [3]string{"o", "o", "o"}},
+	{"か", [3]string{"ka", "ka", "ka"}}, {"き", [3]string{"ki", "ki", "ki"}}, {"く", [3]string{"ku", "ku", "ku"}},
+	{"け", [3]string{"ke", "ke", "ke"}}, {"こ", [3]string{"ko", "ko", "ko"}},
+	{"が", [3]string{"ga", "ga", "ga"}}, {"ぎ", [3]string{"gi", "gi", "gi"}}, {"ぐ", [3]string{"gu", "gu", "gu"}},
+	{"げ", [3]string{"ge", "ge", "ge"}}, {"ご", [3]string{"go", "go", "go"}},
+	{"さ", [3]string{"sa", "sa", "sa"}}, {"し", [3]string{"shi", "si", "si"}}, {"す", [3]string{"su", "su", "su"}},
+	{"せ", [3]string{"se", "se", "se"}}, {"そ", [3]string{"so", "so", "so"}},
+	{"ざ", [3]string{"za", "za", "za"}}, {"じ", [3]string{"ji", "zi", "zi"}}, {"ず", [3]string{"zu", "zu", "zu"}},
+	{"ぜ", [3]string{"ze", "ze", "ze"}}, {"ぞ", [3]string{"zo", "zo", "zo"}},
+	{"た", [3]string{"ta", "ta", "ta"}}, {"ち", [3]string{"chi", "ti", "ti"}}, {"つ", [3]string{"tsu", "tu", "tu"}},
+	{"て", [3]string{"te", "te", "te"}}, {"と", [3]string{"to", "to", "to"}},
+	{"だ", [3]string{"da", "da", "da"}}, {"ぢ", [3]string{"ji", "zi", "di"}}, {"づ", [3]string{"zu", "zu", "du"}},
+	{"で", [3]string{"de", "de", "de"}}, {"ど", [3]string{"do", "do", "do"}},
+	{"な", [3]string{"na", "na", "na"}}, {"に", [3]string{"ni", "ni", "ni"}}, {"ぬ", [3]string{"nu", "nu", "nu"}},
+	{"ね", [3]string{"ne", "ne", "ne"}}, {"の", [3]string{"no", "no", "no"}},
+	{"は", [3]string{"ha", "ha", "ha"}}, {"ひ", [3]string{"hi", "hi", "hi"}}, {"ふ", [3]string{"fu", "hu", "hu"}},
+	{"へ", [3]string{"he", "he", "he"}}, {"ほ", [3]string{"ho", "ho", "ho"}},
+	{"ば", [3]string{"ba", "ba", "ba"}}, {"び", [3]string{"bi", "bi", "bi"}}, {"ぶ", [3]string{"bu", "bu", "bu"}},
+	{"べ", [3]string{"be", "be", "be"}}, {"ぼ", [3]string{"bo", "bo", "bo"}},
+	{"ぱ", [3]string{"pa", "pa", "pa"}}, {"ぴ", [3]string{"pi", "pi", "pi"}}, {"ぷ", [3]string{"pu", "pu", "pu"}},
+	{"ぺ", [3]string{"pe", "pe", "pe"}}, {"ぽ", [3]string{"po", "po", "po"}},
+	{"ま", [3]string{"ma", "ma", "ma"}}, {"み", [3]string{"mi", "mi", "mi"}}, {"む", [3]string{"mu", "mu", "mu"}},
+	{"め", [3]string{"me", "me", "me"}}, {"も", [3]string{"mo", "mo", "mo"}},
+	{"や", [3]string{"ya", "ya", "ya"}}, {"ゆ", [3]string{"yu", "yu", "yu"}}, {"よ", [3]string{"yo", "yo", "yo"}},
+	{"ら", [3]string{"ra", "ra", "ra"}}, {"り", [3]string{"ri", "ri", "ri"}}, {"る", [3]string{"ru", "ru", "ru"}},
+	{"れ", [3]string{"re", "re", "re"}}, {"ろ", [3]string{"ro", "ro", "ro"}},
+	{"わ", [3]string{"wa", "wa", "wa"}}, {"を", [3]string{"o", "o", "wo"}},
+	{"ゔ", [3]string{"vu", "vu", "vu"}},
+}
+
+// romajiExtended lists small kana on their own, the syllables used for loanwords, and obsolete
+// kana.  They come after romajiSyllables and the yōon, so that ti is read as ち rather than ティ.
+var romajiExtended = []struct {
+	kana   string
+	romaji [3]string
+}{
+	{"ぁ", [3]string{"a", "a", "a"}}, {"ぃ", [3]string{"i", "i", "i"}}, {"ぅ", [3]string{"u", "u", "u"}},
+	{"ぇ", [3]string{"e", "e", "e"}}, {"ぉ", [3]string{"o", "o", "o"}},
+	{"ゃ", [3]string{"ya", "ya", "ya"}}, {"ゅ", [3]string{"yu", "yu", "yu"}}, {"ょ", [3]string{"yo", "yo", "yo"}},
+	{"ゎ", [3]string{"wa", "wa", "wa"}},
+
+	{"しぇ", [3]string{"she", "sye", "sye"}}, {"じぇ", [3]string{"je", "zye", "zye"}}, {"ちぇ", [3]string{"che", "tye", "tye"}},
+	{"ふぁ", [3]string{"fa", "fa", "fa"}}, {"ふぃ", [3]string{"fi", "fi", "fi"}}, {"ふぇ", [3]string{"fe", "fe", "fe"}},
+	{"ふぉ", [3]string{"fo", "fo", "fo"}}, {"ふゅ", [3]string{"fyu", "fyu", "fyu"}},
+	{"ゔぁ", [3]string{"va", "va", "va"}}, {"ゔぃ", [3]string{"vi", "vi", "vi"}}, {"ゔぇ", [3]string{"ve", "ve", "ve"}},
+	{"ゔぉ", [3]string{"vo", "vo", "vo"}},
+	{"うぃ", [3]string{"wi", "wi", "wi"}}, {"うぇ", [3]string{"we", "we", "we"}}, {"うぉ", [3]string{"wo", "wo", "wo"}},
+	{"いぇ", [3]string{"ye", "ye", "ye"}},
+	{"つぁ", [3]string{"tsa", "tsa", "tsa"}}, {"つぃ", [3]string{"tsi", "tsi", "tsi"}}, {"つぇ", [3]string{"tse", "tse", "tse"}},
+	{"つぉ", [3]string{"tso", "tso", "tso"}},
+	{"てぃ", [3]string{"ti", "ti", "ti"}}, {"でぃ", [3]string{"di", "di", "di"}},
+	{"とぅ", [3]string{"tu", "tu", "tu"}}, {"どぅ", [3]string{"du", "du", "du"}},
+	{"ゐ", [3]string{"i", "i", "wi"}}, {"ゑ", [3]string{"e", "e", "we"}},
+}
+
+// romajiOnsets lists the consonants of the syllables that combine with ゃ, ゅ and ょ.
+var romajiOnsets = []struct {
+	kana  string
+	onset [3]string
+}{
+	{"き", [3]string{"ky", "ky", "ky"}}, {"ぎ", [3]string{"gy", "gy", "gy"}},
+	{"し", [3]string{"sh", "sy", "sy"}}, {"じ", [3]string{"j", "zy", "zy"}},
+	{"ち", [3]string{"ch", "ty", "ty"}}, {"ぢ", [3]string{"j", "zy", "dy"}},
+	{"に", [3]string{"ny", "ny", "ny"}}, {"ひ", [3]string{"hy", "hy", "hy"}},
+	{"び", [3]string{"by", "by", "by"}}, {"ぴ", [3]string{"py", "py", "py"}},
+	{"み", [3]string{"my", "my", "my"}}, {"り", [3]string{"ry", "ry", "ry"}},
+}
+
+// romajiAliases are other spellings that people type, which are never written.
+var romajiAliases = map[string]string{
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"thi": "てぃ", "dhi": "でぃ", "twu": "とぅ", "dwu": "どぅ",
+	"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+	"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ", "xwa": "ゎ",
+	"xtu": "っ", "xtsu": "っ", "ltu": "っ", "ltsu": "っ",
+}
+
+var (
+	kanaToRomaji = make(map[string][3]string)
+	romajiToKana = make(map[string]string)
+)
+
+func init() {
+	add := func(kana string, romaji [3]string) {
+		kanaToRomaji[kana] = romaji
+		for _, r := range romaji {
+			if _, ok := romajiToKana[r]; !ok {
+				romajiToKana[r] = kana
+			}
+		}
+	}
+
+	for _, s := range romajiSyllables {
+		add(s.kana, s.romaji)
+	}
+	for _, o := range romajiOnsets {
+		for i, small := range []string{"ゃ", "ゅ", "ょ"} {
+			var romaji [3]string
+			for system := range romaji {
+				romaji[system] = o.onset[system] + string("auo"[i])
+			}
+			add(o.kana+small, romaji)
+		}
+	}
+	for _, s := range romajiExtended {
+		add(s.kana, s.romaji)
+	}
+	for romaji, kana := range romajiAliases {
+		if _, ok := romajiToKana[romaji]; !ok {
+			romajiToKana[romaji] = kana
+		}
+	}
+}
+
+// longVowels maps vowels with a macron or circumflex to the plain vowel.
+var longVowels = map[rune]rune{
+	'ā': 'a', 'ī': 'i', 'ū': 'u', 'ē': 'e', 'ō': 'o',
+	'â': 'a', 'î': 'i', 'û': 'u', 'ê': 'e', 'ô': 'o',
+}
+
+// KanaToRomaji writes hiragana or katakana in romaji.  A long vowel written with ー is marked with a
+// macron (Hepburn) or circumflex (Kunrei and Nihon-shiki); one written with kana, like the う of
+// きょう, is spelled out.  Anything that isn't kana is copied as is.
+func KanaToRomaji(kana string, system RomajiSystem) string {
+	var b strings.Builder
+	runes := []rune(katakanaToHiragana(kana))
+
+	for i := 0; i < len(runes); {
+		switch runes[i] {
+		case 'っ':
+			// Double the consonant that follows; Hepburn writes っち as tchi.
+			next, _ := romajiSyllable(runes[i+1:], system)
+			if next != "" && !strings.ContainsRune("aiueo", rune(next[0])) {
+				if system == Hepburn && strings.HasPrefix(next, "ch") {
+					b.WriteByte('t')
+				} else {
+					b.WriteByte(next[0])
+				}
+			}
+			i++
+		case 'ん':
+			// An apostrophe separates ん from a following vowel or y, as in kin'en.
+			b.WriteByte('n')
+			if next, _ := romajiSyllable(runes[i+1:], system); next != "" && strings.ContainsRune("aiueoy", rune(next[0])) {
+				b.WriteByte('\'')
+			}
+			i++
+		case 'ー':
+			out := []rune(b.String())
+			if n := len(out); n > 0 && strings.ContainsRune("aiueo", out[n-1]) {
+				marks := map[rune]rune{'a': 'ā', 'i': 'ī', 'u': 'ū', 'e': 'ē', 'o': 'ō'}
+				if system != Hepburn {
+					marks = map[rune]rune{'a': 'â', 'i': 'î', 'u': 'û', 'e': 'ê', 'o': 'ô'}
+				}
+				out[n-1] = marks[out[n-1]]
+				b.Reset()
+				b.WriteString(string(out))
+			} else {
+				b.WriteByte('-')
+			}
+			i++
+		default:
+			if romaji, n := romajiSyllable(runes[i:], system); n > 0 {
+				b.WriteString(romaji)
+				i += n
+			} else {
+				b.WriteRune(runes[i])
+				i++
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// romajiSyllable returns the romaji for the syllable at the start of runes, and the number of kana
+// it used; 0 if runes doesn't start with a syllable.
+func romajiSyllable(runes []rune, system RomajiSystem) (string, int) {
+	for n := 2; n > 0; n-- {
+		if len(runes) < n {
+			continue
+		}
+		if romaji, ok := kanaToRomaji[string(runes[:n])]; ok {
+			return romaji[system], n
+		}
+	}
+	return "", 0
+}
+
+// RomajiToHiragana reads romaji in any of the RomajiSystems, as well as the spellings that
+// Japanese input methods accept, and writes it in hiragana.  Anything it doesn't recognize is
+// copied as is.
+func RomajiToHiragana(romaji string) string {
+	return romajiToKanaString(romaji, false)
+}
+
+// RomajiToKatakana is like RomajiToHiragana, but writes katakana, with long vowels marked by
+// macrons, circumflexes or hyphens written as ー.
+func RomajiToKatakana(romaji string) string {
+	return romajiToKanaString(romaji, true)
+}
+
+func romajiToKanaString(romaji string, katakana bool) string {
+	// A macron or circumflex marks a long vowel, which is the same as a hyphen after the vowel.
+	var in []rune
+	for _, r := range strings.ToLower(romaji) {
+		if vowel, ok := longVowels[r]; ok {
+			in = append(in, vowel, '-')
+		} else {
+			in = append(in, r)
+		}
+	}
+
+	isVowel := func(i int) bool {
+		return i < len(in) && strings.ContainsRune("aiueo", in[i])
+	}
+
+	var b strings.Builder
+	var lastVowel rune
+	for i := 0; i < len(in); {
+		r := in[i]
+		next := rune(0)
+		if i+1 < len(in) {
+			next = in[i+1]
+		}
+
+		switch {
+		case r == '-':
+			// Hiragana spells the long vowel out; o is lengthened with う.
+			switch {
+			case katakana:
+				b.WriteString("ー")
+			case lastVowel == 'o':
+				b.WriteString("う")
+			case lastVowel != 0:
+				b.WriteString(romajiToKana[string(lastVowel)])
+			default:
+				b.WriteRune(r)
+			}
+			i++
+			continue
+		case r == 'n' && next == '\'':
+			b.WriteString("ん")
+			i += 2
+			continue
+		case r == 'n' && next == 'n' && !isVowel(i+2) && (i+2 >= len(in) || in[i+2] != 'y'):
+			// nn, as typed into an input method.
+			b.WriteString("ん")
+			i += 2
+			continue
+		case r == 'n' && !isVowel(i+1) && next != 'y':
+			b.WriteString("ん")
+			i++
+			continue
+		case r == 'm' && (next == 'b' || next == 'm' || next == 'p'):
+			// Traditional Hepburn writes ん as m before labials, as in shimbun.
+			b.WriteString("ん")
+			i++
+			continue
+		case r == next && r >= 'a' && r <= 'z' && !isVowel(i):
+			b.WriteString("っ")
+			i++
+			continue
+		case r == 't' && next == 'c':
+			// Hepburn tch, as in matcha.
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for n := 4; n > 0; n-- {
+			if i+n > len(in) {
+				continue
+			}
+			if kana, ok := romajiToKana[string(in[i:i+n])]; ok {
+				b.WriteString(kana)
+				lastVowel = in[i+n-1]
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteRune(r)
+			lastVowel = 0
+			i++
+		}
+	}
+
+	if katakana {
+		return hiraganaToKatakana(b.String())
+	}
+	return b.String()
+}
+
+// katakanaToHiragana replaces each katakana character that has a hiragana equivalent with it.
+func katakanaToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'ァ' && r <= 'ヶ') || r == 'ヽ' || r == 'ヾ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+// hiraganaToKatakana replaces each hiragana character with its katakana equivalent.
+func hiraganaToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'ぁ' && r <= 'ゖ') || r == 'ゝ' || r == 'ゞ' {
+			return r + ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+// isKana returns true if s is written entirely in hiragana and katakana.
+func isKana(s string) bool {
+	for _, r := range s {
+		if !unicode.In(r, unicode.Hiragana, unicode.Katakana) && r != 'ー' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Romaji returns the readings of e in romaji: its Kana keys, or for a kana-only word, its Kanji
+// keys.
+func (e Entry) Romaji(system RomajiSystem) []string {
+	readings := e.Kana
+	if len(readings) == 0 {
+		readings = e.Kanji
+	}
+	result := make([]string, 0, len(readings))
+	for _, reading := range readings {
+		result = append(result, KanaToRomaji(reading, system))
+	}
+	return result
+}
+
+// LookupRomaji returns the entries with a kana key that matches query, which is romaji in any of
+// the RomajiSystems, common entries first.
+func (d *Dictionary) LookupRomaji(query string) []*Entry {
+	var result []*Entry
+	seen := make(map[*Entry]bool)
+	for _, q := range longOVariants(query) {
+		key := RomajiToHiragana(q)
+		if !isKana(key) {
+			return nil
+		}
+		for _, entry := range d.LookupAny(key) {
+			if !seen[entry] {
+				seen[entry] = true
+				result = append(result, entry)
+			}
+		}
+	}
+	rank(result)
+	return result
+}
+
+// longOVariants returns query with each long o (ō or ô) written both ways it can be in kana: as
+// "o-", which RomajiToHiragana reads as おう (とうきょう), and as "oo", which it reads as おお
+// (おおきい).
+func longOVariants(query string) []string {
+	result := []string{""}
+	for _, r := range query {
+		var spellings []string
+		switch r {
+		case 'ō', 'ô':
+			spellings = []string{"o-", "oo"}
+		case 'Ō', 'Ô':
+			spellings = []string{"O-", "OO"}
+		default:
+			spellings = []string{string(r)}
+		}
+		next := make([]string, 0, len(result)*len(spellings))
+		for _, prefix := range result {
+			for _, spelling := range spellings {
+				next = append(next, prefix+spelling)
+			}
+		}
+		result = next
+	}
+	return result
+}
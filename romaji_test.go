@@ -0,0 +1,141 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKanaToRomaji(t *testing.T) {
+	testData := []struct {
+		kana   string
+		system RomajiSystem
+		want   string
+	}{
+		{"たべる", Hepburn, "taberu"},
+		{"きょう", Hepburn, "kyou"},
+		{"しんぶん", Hepburn, "shinbun"},
+		{"きんえん", Hepburn, "kin'en"},
+		{"ほんや", Hepburn, "hon'ya"},
+		{"がっこう", Hepburn, "gakkou"},
+		{"まっちゃ", Hepburn, "matcha"},
+		{"まっちゃ", Kunrei, "mattya"},
+		{"カレー", Hepburn, "karē"},
+		{"カレー", Kunrei, "karê"},
+		{"ふじさん", Hepburn, "fujisan"},
+		{"ふじさん", Kunrei, "huzisan"},
+		{"ちぢむ", Kunrei, "tizimu"},
+		{"ちぢむ", NihonShiki, "tidimu"},
+		{"つづく", NihonShiki, "tuduku"},
+		{"をかし", NihonShiki, "wokasi"},
+		{"パーティー", Hepburn, "pātī"},
+		{"ファイル", Hepburn, "fairu"},
+		{"ヴァイオリン", Hepburn, "vaiorin"},
+		{"ジェット", Hepburn, "jetto"},
+		{"ABCかな", Hepburn, "ABCkana"},
+	}
+
+	for _, test := range testData {
+		if got := KanaToRomaji(test.kana, test.system); got != test.want {
+			t.Errorf("%s in system %d:\n   got: %s\n  want: %s", test.kana, test.system, got, test.want)
+		}
+	}
+}
+
+func TestRomajiToKana(t *testing.T) {
+	testData := []struct {
+		romaji   string
+		hiragana string
+		katakana string
+	}{
+		{"taberu", "たべる", "タベル"},
+		{"kyou", "きょう", "キョウ"},
+		{"kyō", "きょう", "キョー"},
+		{"shinbun", "しんぶん", "シンブン"},
+		{"shimbun", "しんぶん", "シンブン"},
+		{"sinbun", "しんぶん", "シンブン"},
+		{"kin'en", "きんえん", "キンエン"},
+		{"kinen", "きねん", "キネン"},
+		{"konnichiha", "こんにちは", "コンニチハ"},
+		{"honnya", "ほんにゃ", "ホンニャ"},
+		{"honn", "ほん", "ホン"},
+		{"gakkou", "がっこう", "ガッコウ"},
+		{"matcha", "まっちゃ", "マッチャ"},
+		{"mattya", "まっちゃ", "マッチャ"},
+		{"karē", "かれえ", "カレー"},
+		{"kare-", "かれえ", "カレー"},
+		{"Tōkyō", "とうきょう", "トーキョー"},
+		{"fairu", "ふぁいる", "ファイル"},
+		{"thi-shatsu", "てぃいしゃつ", "ティーシャツ"},
+		{"tidimu", "ちぢむ", "チヂム"},
+		{"wokasi", "をかし", "ヲカシ"},
+	}
+
+	for _, test := range testData {
+		if got := RomajiToHiragana(test.romaji); got != test.hiragana {
+			t.Errorf("%s to hiragana:\n   got: %s\n  want: %s", test.romaji, got, test.hiragana)
+		}
+		if got := RomajiToKatakana(test.romaji); got != test.katakana {
+			t.Errorf("%s to katakana:\n   got: %s\n  want: %s", test.romaji, got, test.katakana)
+		}
+	}
+}
+
+func TestEntryRomaji(t *testing.T) {
+	entry, err := parseLine("咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) rice and curry/(P)/EntL1039140X/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := entry.Romaji(Hepburn), []string{"karē", "karī"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readings:\n   got: %v\n  want: %v", got, want)
+	}
+}
+
+func TestLookupRomaji(t *testing.T) {
+	d := newTestDictionary(t)
+
+	testData := []struct {
+		query string
+		want  []string
+	}{
+		{"karē", []string{"EntL1039140", "EntL1039150", "EntL9000002"}},
+		{"KARĪ", []string{"EntL1039140", "EntL9000001"}},
+		{"getsu", []string{"EntL2542160"}},
+		{"getu", []string{"EntL2542160"}},
+		{"sonou", []string{"EntL2542030"}},
+		{"curry", nil},
+	}
+
+	for _, test := range testData {
+		if got := sequences(d.LookupRomaji(test.query)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("lookup %s:\n   got: %v\n  want: %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestLookupRomajiLongO(t *testing.T) {
+	input := strings.Join([]string{
+		"大きい [おおきい] /(adj-i) big/(P)/EntL1155000X/",
+		"東京 [とうきょう] /(n) Tokyo/(P)/EntL1433300X/",
+	}, "\n")
+	d, err := ReadDictionary(NewReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		query string
+		want  []string
+	}{
+		{"ōkii", []string{"EntL1155000"}},
+		{"ookii", []string{"EntL1155000"}},
+		{"tōkyō", []string{"EntL1433300"}},
+		{"TÔKYÔ", []string{"EntL1433300"}},
+	}
+
+	for _, test := range testData {
+		if got := sequences(d.LookupRomaji(test.query)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("lookup %s:\n   got: %v\n  want: %v", test.query, got, test.want)
+		}
+	}
+}
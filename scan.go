@@ -0,0 +1,65 @@
+package edict
+
+import "unicode/utf8"
+
+// ScanMode selects which words Dictionary.Scan reports.
+type ScanMode int
+
+const (
+	// Greedy takes the longest word at each position and continues after it, like a reader
+	// segmenting a sentence.  Characters that don't start any word are skipped.
+	Greedy ScanMode = iota
+	// AllCandidates reports every word starting at every position, longest first, for showing
+	// alternatives in a popup.
+	AllCandidates
+)
+
+// scanWindow is the longest run of characters that Scan tries to look up; enough for long
+// compounds and heavily inflected verbs like 食べさせられませんでした.
+const scanWindow = 16
+
+// Span is a word that Dictionary.Scan found in text.
+type Span struct {
+	Start, End int              // Byte offsets of the word in the scanned text.
+	Text       string           // The word as it appears in the text.
+	Candidates []InflectedMatch // The entries that it could be, as returned by LookupInflected.
+}
+
+// Scan finds dictionary words in running Japanese text.  At each position, it looks up the
+// longest run of characters that is a kanji or kana key, or an inflected form of one, and works
+// back to shorter runs.
+func (d *Dictionary) Scan(text string, mode ScanMode) []Span {
+	var result []Span
+
+	for start := 0; start < len(text); {
+		// ends holds the byte offset after each of the next scanWindow characters.
+		var ends []int
+		for end := start; end < len(text) && len(ends) < scanWindow; {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			ends = append(ends, end)
+		}
+
+		found := false
+		for i := len(ends) - 1; i >= 0; i-- {
+			word := text[start:ends[i]]
+			matches := d.LookupInflected(word)
+			if len(matches) == 0 {
+				continue
+			}
+			result = append(result, Span{Start: start, End: ends[i], Text: word, Candidates: matches})
+			found = true
+			if mode == Greedy {
+				break
+			}
+		}
+
+		if found && mode == Greedy {
+			start = result[len(result)-1].End
+		} else {
+			start = ends[0]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,65 @@
+package edict
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testScanDictionary = []string{
+	"私 [わたし] /(pn) I/me/(P)/EntL1311110X/",
+	"は /(prt) topic marker particle/(P)/EntL2028920X/",
+	"カレー /(n) curry/(P)/EntL1039150/",
+	"カレーライス /(n) curry rice/(P)/EntL1039170X/",
+	"ライス /(n) rice/(P)/EntL1136480X/",
+	"を /(prt) object marker particle/(P)/EntL2029010X/",
+	"食べる [たべる] /(v1,vt) to eat/(P)/EntL1358280X/",
+}
+
+func TestScan(t *testing.T) {
+	d, err := ReadDictionary(NewReader(strings.NewReader(strings.Join(testScanDictionary, "\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "私はカレーライスを食べなかった。"
+
+	type span struct {
+		Text     string
+		Sequence string
+		Word     string
+	}
+	spans := func(mode ScanMode) []span {
+		var result []span
+		for _, s := range d.Scan(text, mode) {
+			if text[s.Start:s.End] != s.Text {
+				t.Errorf("span %q has offsets %d-%d, which hold %q", s.Text, s.Start, s.End, text[s.Start:s.End])
+			}
+			result = append(result, span{s.Text, s.Candidates[0].Entry.Sequence, s.Candidates[0].Word})
+		}
+		return result
+	}
+
+	greedy := []span{
+		{"私", "EntL1311110", "私"},
+		{"は", "EntL2028920", "は"},
+		{"カレーライス", "EntL1039170", "カレーライス"},
+		{"を", "EntL2029010", "を"},
+		{"食べなかった", "EntL1358280", "食べる"},
+	}
+	if got := spans(Greedy); !reflect.DeepEqual(got, greedy) {
+		t.Errorf("greedy scan:\n   got: %v\n  want: %v", got, greedy)
+	}
+
+	all := []span{
+		{"私", "EntL1311110", "私"},
+		{"は", "EntL2028920", "は"},
+		{"カレーライス", "EntL1039170", "カレーライス"},
+		{"カレー", "EntL1039150", "カレー"},
+		{"ライス", "EntL1136480", "ライス"},
+		{"を", "EntL2029010", "を"},
+		{"食べなかった", "EntL1358280", "食べる"},
+	}
+	if got := spans(AllCandidates); !reflect.DeepEqual(got, all) {
+		t.Errorf("all candidates scan:\n   got: %v\n  want: %v", got, all)
+	}
+}
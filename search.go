@@ -0,0 +1,153 @@
+package edict
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// keyIndex holds every kanji and kana key, sorted forward and backward, so that keys with a given
+// prefix or suffix are a contiguous range that can be found by binary search.
+type keyIndex struct {
+	forward  []string // Every key, sorted.
+	backward []string // Every key with its characters reversed, sorted.
+}
+
+func newKeyIndex(keys map[string][]*Entry) keyIndex {
+	result := keyIndex{
+		forward:  make([]string, 0, len(keys)),
+		backward: make([]string, 0, len(keys)),
+	}
+	for key := range keys {
+		result.forward = append(result.forward, key)
+		result.backward = append(result.backward, reverse(key))
+	}
+	sort.Strings(result.forward)
+	sort.Strings(result.backward)
+	return result
+}
+
+// reverse reverses the characters of s.
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// withPrefix returns the elements of sorted that start with prefix.
+func withPrefix(sorted []string, prefix string) []string {
+	start := sort.SearchStrings(sorted, prefix)
+	end := start
+	for end < len(sorted) && strings.HasPrefix(sorted[end], prefix) {
+		end++
+	}
+	return sorted[start:end]
+}
+
+// SearchPrefix returns the entries with a kanji or kana key that starts with prefix.
+func (d *Dictionary) SearchPrefix(prefix string) []*Entry {
+	return d.collect(withPrefix(d.keys.forward, NormalizeKana(prefix)))
+}
+
+// SearchSuffix returns the entries with a kanji or kana key that ends with suffix.
+func (d *Dictionary) SearchSuffix(suffix string) []*Entry {
+	var keys []string
+	for _, key := range withPrefix(d.keys.backward, reverse(NormalizeKana(suffix))) {
+		keys = append(keys, reverse(key))
+	}
+	return d.collect(keys)
+}
+
+// SearchPattern returns the entries with a kanji or kana key that matches pattern, where '*'
+// matches any number of characters and '?' matches exactly one, like 食べ*, *ぶ, or ?ちご.  The
+// literal text at the start or end of the pattern is used to narrow the search, so patterns that
+// start and end with wildcards have to check every key.  A ー that follows a wildcard, as in カ?ー,
+// matches any vowel.
+func (d *Dictionary) SearchPattern(pattern string) []*Entry {
+	pattern = NormalizeKana(pattern)
+	if !strings.ContainsAny(pattern, "*?") {
+		return d.collect([]string{pattern})
+	}
+
+	// NormalizeKana can't tell which vowel a ー after a wildcard stands for, so it can't be used
+	// to narrow the search either.
+	prefix := pattern[:strings.IndexAny(pattern, "*?ー")]
+	last := strings.LastIndexAny(pattern, "*?ー")
+	_, size := utf8.DecodeRuneInString(pattern[last:])
+	suffix := pattern[last+size:]
+
+	var candidates []string
+	switch {
+	case prefix != "" && len(prefix) >= len(suffix):
+		candidates = withPrefix(d.keys.forward, prefix)
+	case suffix != "":
+		for _, key := range withPrefix(d.keys.backward, reverse(suffix)) {
+			candidates = append(candidates, reverse(key))
+		}
+	default:
+		candidates = d.keys.forward
+	}
+
+	patternRunes := []rune(pattern)
+	var keys []string
+	for _, key := range candidates {
+		if matchGlob(patternRunes, []rune(key)) {
+			keys = append(keys, key)
+		}
+	}
+	return d.collect(keys)
+}
+
+// collect returns the entries for keys, shortest keys first, then ranked like the other lookups.
+// keys may be part of the index, so it's copied before sorting.
+func (d *Dictionary) collect(keys []string) []*Entry {
+	keys = append([]string(nil), keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		if li, lj := utf8.RuneCountInString(keys[i]), utf8.RuneCountInString(keys[j]); li != lj {
+			return li < lj
+		}
+		return keys[i] < keys[j]
+	})
+
+	var result []*Entry
+	seen := make(map[*Entry]bool)
+	for _, key := range keys {
+		for _, entry := range d.any[key] {
+			if !seen[entry] {
+				seen[entry] = true
+				result = append(result, entry)
+			}
+		}
+	}
+	rank(result)
+	return result
+}
+
+// matchGlob returns true if s matches pattern, where '*' matches any run of characters, '?'
+// matches any single character, and 'ー' matches any hiragana vowel.
+func matchGlob(pattern, s []rune) bool {
+	// star and match remember the position of the last '*' and how much of s it has consumed,
+	// so that we can backtrack and let it consume one more character.
+	p, i, star, match := 0, 0, -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i] || pattern[p] == 'ー' && strings.ContainsRune("あいうえお", s[i])):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, i
+			p++
+		case star >= 0:
+			match++
+			p, i = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,60 @@
+package edict
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	testData := []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"食べ*", "食べる", true},
+		{"食べ*", "食べ", true},
+		{"食べ*", "食う", false},
+		{"*ぶ", "あそぶ", true},
+		{"*ぶ", "あそぶな", false},
+		{"?ちご", "いちご", true},
+		{"?ちご", "ちご", false},
+		{"*", "", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"a?*", "a", false},
+	}
+
+	for _, test := range testData {
+		if got := matchGlob([]rune(test.pattern), []rune(test.s)); got != test.match {
+			t.Errorf("matching %q against %q: got %v, want %v", test.s, test.pattern, got, test.match)
+		}
+	}
+}
+
+func TestSearchPattern(t *testing.T) {
+	d := newTestDictionary(t)
+
+	testData := []struct {
+		name   string
+		search func(string) []*Entry
+		query  string
+		want   []string
+	}{
+		{"prefix", d.SearchPrefix, "カ", []string{"EntL1039140", "EntL1039150", "EntL9000001", "EntL9000002"}},
+		{"prefix", d.SearchPrefix, "カレ", []string{"EntL1039140", "EntL1039150", "EntL9000002"}},
+		{"prefix", d.SearchPrefix, "キ", nil},
+		{"suffix", d.SearchSuffix, "嚢", []string{"EntL2542030"}},
+		{"suffix", d.SearchSuffix, "リー", []string{"EntL1039140", "EntL9000001"}},
+		{"pattern", d.SearchPattern, "カ?ー", []string{"EntL1039140", "EntL1039150", "EntL9000001", "EntL9000002"}},
+		{"pattern", d.SearchPattern, "*のう", []string{"EntL2542030"}},
+		{"pattern", d.SearchPattern, "?嚢", []string{"EntL2542030"}},
+		{"pattern", d.SearchPattern, "*哩*", []string{"EntL1039140"}},
+		{"pattern", d.SearchPattern, "げつ", []string{"EntL2542160"}},
+	}
+
+	for _, test := range testData {
+		got := sequences(test.search(test.query))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s search for %s:\n   got: %v\n  want: %v", test.name, test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package edict
+
+import "strings"
+
+// Source is the origin of a loanword (gairaigo), like the German Arbeit for アルバイト.
+type Source struct {
+	Lang  string `json:"lang"`            // The ISO 639-2 code of the language, like "ger".
+	Word  string `json:"word,omitempty"`  // The word in that language, if given.
+	Wasei bool   `json:"wasei,omitempty"` // True for wasei-eigo, words made in Japan from English parts.
+}
+
+// sourceLanguages are the ISO 639-2 codes that JMdict uses for the origins of loanwords.
+var sourceLanguages = map[string]bool{
+	"afr": true, "ain": true, "alg": true, "amh": true, "ara": true, "arn": true, "bnt": true,
+	"bre": true, "bul": true, "bur": true, "chi": true, "chn": true, "cze": true, "dan": true,
+	"dut": true, "eng": true, "epo": true, "est": true, "fil": true, "fin": true, "fre": true,
+	"geo": true, "ger": true, "glg": true, "grc": true, "gre": true, "haw": true, "heb": true,
+	"hin": true, "hun": true, "ice": true, "ind": true, "ita": true, "khm": true, "kor": true,
+	"kur": true, "lat": true, "lit": true, "mal": true, "mao": true, "may": true, "mnc": true,
+	"mol": true, "mon": true, "nor": true, "per": true, "pol": true, "por": true, "rum": true,
+	"rus": true, "san": true, "scr": true, "slo": true, "slv": true, "som": true, "spa": true,
+	"swa": true, "swe": true, "tah": true, "tam": true, "tgl": true, "tha": true, "tib": true,
+	"tur": true, "ukr": true, "urd": true, "uzb": true, "vie": true, "yid": true,
+}
+
+// parseSource parses an identifier like "fre: avec", "ger:" or "wasei: after service", or several of
+// them separated by commas, like "fre: avec, ger: mit", into one Source per language.  Wasei-eigo
+// is marked with "wasei" in place of the language, and is always from English.  A comma that isn't
+// followed by a language is part of the word, as in "eng: bread, butter".
+func parseSource(s string) ([]Source, bool) {
+	var result []Source
+	for _, part := range strings.Split(s, ",") {
+		if source, ok := parseOneSource(strings.TrimSpace(part)); ok {
+			result = append(result, source)
+		} else if len(result) > 0 {
+			result[len(result)-1].Word += "," + part
+		} else {
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+// parseOneSource parses a single language and word, like "fre: avec".
+func parseOneSource(s string) (Source, bool) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return Source{}, false
+	}
+	lang, word := s[:i], strings.TrimSpace(s[i+1:])
+	switch {
+	case lang == "wasei":
+		return Source{Lang: "eng", Word: word, Wasei: true}, true
+	case sourceLanguages[lang]:
+		return Source{Lang: lang, Word: word}, true
+	}
+	return Source{}, false
+}
+
+// String formats s the way edict2 writes it, without the parentheses.  edict2 can only mark
+// wasei-eigo from English.
+func (s Source) String() string {
+	lang := s.Lang
+	if s.Wasei && s.Lang == "eng" {
+		lang = "wasei"
+	}
+	if s.Word == "" {
+		return lang + ":"
+	}
+	return lang + ": " + s.Word
+}
@@ -0,0 +1,192 @@
+package edict
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Writer writes entries as edict2 lines.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter returns a Writer that writes to w.  Call Flush when done.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes e as a single edict2 line.  If e can't be written, as for Marshal, nothing is
+// written and the error is returned.
+func (w *Writer) Write(e Entry) error {
+	line, err := Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.WriteString(line); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Marshal formats e as an edict2 line, without the trailing newline.  Parsing the result gives back
+// e, except for information that edict2 can't represent: details of a lone gloss are merged into
+// the entry's, Common is moved to the end of the entry's details, a Note becomes part of the
+// definition, and the other fields marked "JMdict only" are lost.  Entries with name types, like
+// Surname, are written as ENAMDICT, and read back with NewNameReader.
+//
+// edict2 separates fields with '/', and only keeps one together when it's inside parentheses, like
+// "(AC/DC)".  Marshal returns an error for an entry with a '/' anywhere else, rather than write a
+// line that reads back as something different.
+func Marshal(e Entry) (string, error) {
+	var b strings.Builder
+
+	keys := formatKeys(e.Kanji, e.KanjiKeys) + formatKeys(e.Kana, e.KanaKeys)
+	if strings.ContainsRune(keys, '/') {
+		return "", fmt.Errorf("marshal %s: keys can't contain '/'", e.Sequence)
+	}
+	// The key, each gloss, and the empty field after the last '/'.
+	fields := 2 + len(e.Gloss)
+
+	b.WriteString(formatKeys(e.Kanji, e.KanjiKeys))
+	if len(e.Kana) > 0 {
+		b.WriteString(" [")
+		b.WriteString(formatKeys(e.Kana, e.KanaKeys))
+		b.WriteString("]")
+	}
+	b.WriteString(" /")
+
+	var information []Detail
+	common := false
+	for _, d := range e.Information {
+		if d == Common {
+			common = true
+		} else {
+			information = append(information, d)
+		}
+	}
+
+	// parseLine only looks for entry-wide details before a (1) marker when there's more than
+	// one field, and only numbers senses when there's more than one of them.
+	multiple := len(e.Gloss) > 1 || common && len(e.Gloss) > 0
+	numbered := false
+	for _, g := range e.Gloss {
+		numbered = numbered || g.Sense > 1
+	}
+
+	for i, g := range e.Gloss {
+		var parts []string
+		if i == 0 {
+			if details := formatDetails(information); details != "" {
+				parts = append(parts, details)
+			}
+		}
+		if numbered && (i == 0 || g.Sense != e.Gloss[i-1].Sense) || i == 0 && multiple && len(information) > 0 {
+			sense := g.Sense
+			if sense < 1 {
+				sense = 1
+			}
+			parts = append(parts, "("+strconv.Itoa(sense)+")")
+		}
+		if details := formatDetails(g.Information); details != "" {
+			parts = append(parts, details)
+		}
+		for _, xref := range g.Xref {
+			parts = append(parts, "(See "+xref.String()+")")
+		}
+		for _, ant := range g.Antonym {
+			parts = append(parts, "(ant: "+ant.String()+")")
+		}
+		if len(g.Source) > 0 {
+			// Like edict2, (fre: avec, ger: mit).
+			var sources []string
+			for _, source := range g.Source {
+				sources = append(sources, source.String())
+			}
+			parts = append(parts, "("+strings.Join(sources, ", ")+")")
+		}
+		if g.Note != "" {
+			parts = append(parts, "("+g.Note+")")
+		}
+		parts = append(parts, g.Definition)
+
+		gloss := strings.Join(parts, " ")
+		if len(splitFields(gloss)) != 1 {
+			return "", fmt.Errorf("marshal %s: gloss %d: '/' outside parentheses in %q", e.Sequence, i+1, gloss)
+		}
+		b.WriteString(gloss)
+		b.WriteString("/")
+	}
+
+	if common {
+		b.WriteString("(P)/")
+		fields++
+	}
+	if e.Sequence != "" {
+		b.WriteString(e.Sequence)
+		if e.RecordingAvailable {
+			b.WriteString("X")
+		}
+		b.WriteString("/")
+		fields++
+	}
+
+	// A '(' that one gloss leaves open can still swallow the glosses after it.
+	line := b.String()
+	if len(splitFields(line)) != fields {
+		return "", fmt.Errorf("marshal %s: unbalanced parentheses would join glosses", e.Sequence)
+	}
+	return line, nil
+}
+
+// formatKeys formats a list of keys, with their annotations if they're available.
+func formatKeys(text []string, keys []Key) string {
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = t
+		if len(keys) != len(text) {
+			continue
+		}
+
+		key := keys[i]
+		for _, d := range key.Information {
+			result[i] += "(" + d.String() + ")"
+		}
+		if len(key.Restrict) > 0 {
+			result[i] += "(" + strings.Join(key.Restrict, ";") + ")"
+		}
+		for _, other := range key.Other {
+			result[i] += "(" + other + ")"
+		}
+		if key.Common {
+			result[i] += "(P)"
+		}
+	}
+	return strings.Join(result, ";")
+}
+
+// formatDetails formats details the way edict2 does, with runs of parts of speech (or name types)
+// grouped together like (n,vs) and everything else in its own parentheses.
+func formatDetails(details []Detail) string {
+	grouped := func(d Detail) bool {
+		return d.IsPartOfSpeech() || d.IsNameType()
+	}
+
+	var result []string
+	for i, d := range details {
+		if i > 0 && grouped(d) && grouped(details[i-1]) {
+			last := result[len(result)-1]
+			result[len(result)-1] = strings.TrimSuffix(last, ")") + "," + d.String() + ")"
+		} else {
+			result = append(result, "("+d.String()+")")
+		}
+	}
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,121 @@
+package edict
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// These are already in the canonical form, so they should be written back out unchanged.
+var testLines = []string{
+	"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee (form of punishment in ancient China)/EntL2542160/",
+	"匜;半挿 [はそう;はぞう] /(n) (1) (esp. ) wide-mouthed ceramic vessel having a small hole in its spherical base (into which bamboo was probably inserted to pour liquids)/(2) (See 半挿・はんぞう・1) teapot-like object made typically of lacquerware and used to pour hot and cold liquids/EntL2791750/",
+	"咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140X/",
+	"嗉嚢;そ嚢 [そのう] /(n) bird's crop/bird's craw/EntL2542030/",
+	"嘈囃;そう囃(iK) [そうざつ(嘈囃;そう囃)(P);むねやけ(嘈囃)] /(n,vs) (obsc) (嘈囃 is sometimes read むねやけ) (See 胸焼け) heartburn/sour stomach/EntL2542040/",
+	"ジョン;Jon [じょん] /(n) (1) (abbr) (uK) (See jrockway) my name/(2) (uk) apparently a common name for dogs/EntL0000000/",
+	"カレー /(n) (1) curry/rice/(P)/EntL1039150/",
+	"阿部 [あべ] /(s,p) Abe/",
+	"暑い [あつい] /(adj-i) (ant: 寒い・さむい・1) hot (weather, etc.)/EntL1586420/",
+	"アフターサービス /(n) (wasei: after service) after-sales service/EntL1016990/",
+	"アベック /(n) (fre: avec, ger: mit) couple/EntL1000000/",
+	"嘈囃(sK);そう囃 [そうざつ(嘈囃)(P)] /(n) heartburn/EntL2542040/",
+}
+
+func TestMarshal(t *testing.T) {
+	for _, line := range testLines {
+		entry, err := parseLine(line)
+		if err != nil {
+			t.Fatalf("%s: %s", line, err)
+		}
+
+		got, err := Marshal(entry)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", line, err)
+		} else if got != line {
+			t.Errorf("unexpected line\n   got: %s\n  want: %s", got, line)
+		}
+	}
+}
+
+func TestMarshalSeparator(t *testing.T) {
+	entry := func(defs ...string) Entry {
+		e := Entry{Kanji: []string{"時速"}, Kana: []string{"じそく"}, Sequence: "EntL0000004"}
+		for _, d := range defs {
+			e.Gloss = append(e.Gloss, Gloss{Definition: d, Sense: 1})
+		}
+		return e
+	}
+
+	// A '/' inside parentheses reads back as part of the gloss.
+	want := entry("speed (in km/h)")
+	line, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseLine(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Gloss[0].Definition != want.Gloss[0].Definition || len(got.Gloss) != 1 {
+		t.Errorf("round trip of %s:\n   got: %v\n  want: %v", line, got.Gloss, want.Gloss)
+	}
+
+	// Anywhere else, it would split the gloss, so it's an error.
+	for _, e := range []Entry{
+		entry("a/b"),
+		entry("km/h", "speed"),
+		entry("speed (in km", "h)"),
+		{Kanji: []string{"Ｉ/Ｏ"}, Gloss: []Gloss{{Definition: "input-output", Sense: 1}}},
+	} {
+		if line, err := Marshal(e); err == nil {
+			t.Errorf("%v: expected an error, got %s", e, line)
+		}
+		if err := NewWriter(new(bytes.Buffer)).Write(e); err == nil {
+			t.Errorf("%v: expected an error from Write", e)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want, err := Parse(strings.NewReader(strings.Join(testLines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries that didn't come from edict2 should survive too, as long as they only use
+	// information that edict2 can represent.
+	want = append(want, Entry{
+		Kanji:       []string{"食べる"},
+		Kana:        []string{"たべる"},
+		KanjiKeys:   []Key{{Text: "食べる", Common: true}},
+		KanaKeys:    []Key{{Text: "たべる", Common: true}},
+		Information: []Detail{V1, Vt, Common},
+		Gloss: []Gloss{
+			{Definition: "to eat", Sense: 1},
+			{Definition: "to live on (e.g. a salary)", Information: []Detail{Col}, Sense: 2},
+			{Definition: "to live off", Sense: 2},
+		},
+		Sequence: "EntL1358280",
+	})
+
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	for _, entry := range want {
+		if err := w.Write(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Parse(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip failed\n   got: %+v\n  want: %+v", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package edict
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Xref is a cross-reference to another entry, written in edict2 and JMdict like 半挿・はんぞう・1:
+// a key, optionally the reading, and optionally the sense number.
+type Xref struct {
+	Kanji string `json:"kanji"`           // The referenced key; for kana-only words, the kana.
+	Kana  string `json:"kana,omitempty"`  // The reading of Kanji, if the reference gives one.
+	Sense int    `json:"sense,omitempty"` // The referenced sense, starting at 1; 0 for the whole entry.
+}
+
+// ParseXref parses a cross-reference like 半挿・はんぞう・1.  Katakana words can contain ・ too, as in
+// ジョン・スミス, so when the first part is kana, everything up to the sense number is the key.  It
+// returns an error if the key or any other part is empty, as in ・1.
+func ParseXref(s string) (Xref, error) {
+	result := Xref{}
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "・")
+	for _, part := range parts {
+		if part == "" {
+			return result, fmt.Errorf("empty part in cross-reference %q", s)
+		}
+	}
+	if n, err := strconv.Atoi(parts[len(parts)-1]); err == nil && len(parts) > 1 {
+		result.Sense = n
+		parts = parts[:len(parts)-1]
+	}
+
+	switch {
+	case isKana(parts[0]):
+		result.Kanji = strings.Join(parts, "・")
+	case len(parts) > 1:
+		result.Kanji, result.Kana = parts[0], strings.Join(parts[1:], "・")
+	default:
+		result.Kanji = parts[0]
+	}
+	return result, nil
+}
+
+// String formats x the way edict2 writes it.
+func (x Xref) String() string {
+	parts := []string{x.Kanji}
+	if x.Kana != "" {
+		parts = append(parts, x.Kana)
+	}
+	if x.Sense > 0 {
+		parts = append(parts, strconv.Itoa(x.Sense))
+	}
+	return strings.Join(parts, "・")
+}
+
+// ResolveXref finds the entry that x refers to, and the index in its Gloss of the first gloss of the
+// referenced sense (0 if x doesn't name one).  If several entries match, the first one that
+// LookupAny returns wins.  It returns false if no entry matches.
+func (d *Dictionary) ResolveXref(x Xref) (*Entry, int, bool) {
+	for _, entry := range d.LookupAny(x.Kanji) {
+		if x.Kana != "" && !containsNormalized(entry.Kana, x.Kana) && !containsNormalized(entry.Kanji, x.Kana) {
+			continue
+		}
+		if x.Sense == 0 {
+			return entry, 0, true
+		}
+		for i, gloss := range entry.Gloss {
+			if gloss.Sense == x.Sense {
+				return entry, i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// containsNormalized is like contains, but compares keys after NormalizeKana.
+func containsNormalized(list []string, s string) bool {
+	s = NormalizeKana(s)
+	for _, item := range list {
+		if NormalizeKana(item) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// DanglingXref is a cross-reference that ResolveXref can't resolve.
+type DanglingXref struct {
+	Entry *Entry // The entry containing the reference.
+	Gloss int    // The index in Entry.Gloss of the gloss containing the reference.
+	Xref  Xref
+}
+
+// DanglingXrefs returns every cross-reference and antonym in the dictionary that doesn't refer to
+// an entry in it, in dictionary order.
+func (d *Dictionary) DanglingXrefs() []DanglingXref {
+	var result []DanglingXref
+	for i := range d.entries {
+		entry := &d.entries[i]
+		for g, gloss := range entry.Gloss {
+			for _, x := range append(append([]Xref{}, gloss.Xref...), gloss.Antonym...) {
+				if _, _, ok := d.ResolveXref(x); !ok {
+					result = append(result, DanglingXref{Entry: entry, Gloss: g, Xref: x})
+				}
+			}
+		}
+	}
+	return result
+}
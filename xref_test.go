@@ -0,0 +1,111 @@
+package edict
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseXref(t *testing.T) {
+	testData := []struct {
+		input string
+		want  Xref
+	}{
+		{"剕", Xref{Kanji: "剕"}},
+		{"半挿・はんぞう", Xref{Kanji: "半挿", Kana: "はんぞう"}},
+		{"半挿・はんぞう・1", Xref{Kanji: "半挿", Kana: "はんぞう", Sense: 1}},
+		{"カレーライス・2", Xref{Kanji: "カレーライス", Sense: 2}},
+		{"ジョン・スミス", Xref{Kanji: "ジョン・スミス"}},
+		{"ジョン・スミス・3", Xref{Kanji: "ジョン・スミス", Sense: 3}},
+		{"１２３", Xref{Kanji: "１２３"}},
+	}
+
+	for _, test := range testData {
+		got, err := ParseXref(test.input)
+		if err != nil {
+			t.Errorf("parse %s: unexpected error: %s", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parse %s:\n   got: %#v\n  want: %#v", test.input, got, test.want)
+		}
+		if got.String() != test.input {
+			t.Errorf("format %#v: got %s, want %s", got, got.String(), test.input)
+		}
+	}
+
+	for _, input := range []string{"", "・1", "・", "半挿・", "半挿・・1"} {
+		if got, err := ParseXref(input); err == nil {
+			t.Errorf("parse %q: expected an error, got %#v", input, got)
+		}
+	}
+	if _, err := parseGloss("(See 半挿,・1) teapot", DetailFor); err == nil {
+		t.Error("expected an error for an empty cross-reference in a gloss")
+	}
+
+	xrefs := []Xref{{Kanji: "半挿", Kana: "はんぞう", Sense: 1}, {Kanji: "剕"}}
+	encoded, err := json.Marshal(xrefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"kanji":"半挿","kana":"はんぞう","sense":1},{"kanji":"剕"}]`; string(encoded) != want {
+		t.Errorf("json: got %s, want %s", encoded, want)
+	}
+	var decoded []Xref
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, xrefs) {
+		t.Errorf("json round trip: got %v, want %v", decoded, xrefs)
+	}
+}
+
+func TestResolveXref(t *testing.T) {
+	lines := []string{
+		"匜;半挿 [はそう;はぞう] /(n) (1) wide-mouthed ceramic vessel/(2) (See 半挿・はんぞう・1) teapot-like object/EntL2791750/",
+		"半挿;楾 [はんぞう;はぞう] /(n) (1) (See 匜・1) teapot-like object/(2) (See 角盥) basin/EntL2791760/",
+		"咖哩(ateji) [カレー(P);カリー] /(n) (1) (uk) curry/(2) (abbr) (uk) (See カレーライス) rice and curry/(P)/EntL1039140X/",
+		"刖 [げつ] /(n) (arch) (obsc) (See 剕) cutting off the leg at the knee/EntL2542160/",
+		"暑い [あつい] /(adj-i) (ant: 寒い・さむい・1) hot (weather, etc.)/EntL1586420/",
+	}
+	d, err := ReadDictionary(NewReader(strings.NewReader(strings.Join(lines, "\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		xref     Xref
+		sequence string
+		gloss    int
+	}{
+		{Xref{Kanji: "半挿", Kana: "はんぞう", Sense: 1}, "EntL2791760", 0},
+		{Xref{Kanji: "半挿", Kana: "はそう"}, "EntL2791750", 0},
+		{Xref{Kanji: "半挿", Sense: 2}, "EntL2791750", 1},
+		{Xref{Kanji: "かれー", Sense: 2}, "EntL1039140", 1},
+		{Xref{Kanji: "半挿", Kana: "はんぞう", Sense: 3}, "", 0},
+		{Xref{Kanji: "剕"}, "", 0},
+	}
+
+	for _, test := range testData {
+		entry, gloss, ok := d.ResolveXref(test.xref)
+		if test.sequence == "" {
+			if ok {
+				t.Errorf("resolve %v: got %s, want nothing", test.xref, entry.Sequence)
+			}
+			continue
+		}
+		if !ok || entry.Sequence != test.sequence || gloss != test.gloss {
+			t.Errorf("resolve %v: got %v, %d, %v; want %s, %d", test.xref, entry, gloss, ok, test.sequence, test.gloss)
+		}
+	}
+
+	var dangling []string
+	for _, x := range d.DanglingXrefs() {
+		dangling = append(dangling, x.Entry.Sequence+" "+x.Xref.String())
+	}
+	want := []string{"EntL2791760 角盥", "EntL1039140 カレーライス", "EntL2542160 剕", "EntL1586420 寒い・さむい・1"}
+	if !reflect.DeepEqual(dangling, want) {
+		t.Errorf("dangling xrefs:\n   got: %v\n  want: %v", dangling, want)
+	}
+}